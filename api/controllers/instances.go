@@ -82,3 +82,23 @@ func InstanceTerminate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 
 	return RenderSuccess(rw)
 }
+
+func InstanceCordon(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	id := mux.Vars(r)["id"]
+
+	if err := Provider.InstanceCordon(id); err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderSuccess(rw)
+}
+
+func InstanceUncordon(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	id := mux.Vars(r)["id"]
+
+	if err := Provider.InstanceUncordon(id); err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderSuccess(rw)
+}
@@ -38,7 +38,7 @@ func TestInstanceList(t *testing.T) {
 
 		if assert.Nil(t, hf.Request("GET", "/instances", nil)) {
 			hf.AssertCode(t, 200)
-			hf.AssertJSON(t, "[{\"agent\":true,\"cpu\":0.28,\"id\":\"test\",\"memory\":0.18,\"private-ip\":\"1.2.3.4\",\"processes\":5,\"public-ip\":\"2.3.4.5\",\"started\":\"2016-10-04T19:46:00Z\",\"status\":\"running\"}]")
+			hf.AssertJSON(t, "[{\"agent\":true,\"cordoned\":false,\"cpu\":0.28,\"id\":\"test\",\"memory\":0.18,\"private-ip\":\"1.2.3.4\",\"processes\":5,\"public-ip\":\"2.3.4.5\",\"started\":\"2016-10-04T19:46:00Z\",\"status\":\"running\"}]")
 		}
 	})
 }
@@ -66,3 +66,40 @@ func TestInstanceTerminate(t *testing.T) {
 		}
 	})
 }
+
+func TestInstanceCordon(t *testing.T) {
+	Mock(func(p *structs.MockProvider) {
+		p.On("InstanceCordon", "i-1234").Return(nil)
+
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		if assert.Nil(t, hf.Request("POST", "/instances/i-1234/cordon", nil)) {
+			hf.AssertCode(t, 200)
+			hf.AssertSuccess(t)
+		}
+	})
+
+	Mock(func(p *structs.MockProvider) {
+		p.On("InstanceCordon", "i-1234").Return(fmt.Errorf("no such instance: i-1234"))
+
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		if assert.Nil(t, hf.Request("POST", "/instances/i-1234/cordon", nil)) {
+			hf.AssertCode(t, 500)
+			hf.AssertError(t, "no such instance: i-1234")
+		}
+	})
+}
+
+func TestInstanceUncordon(t *testing.T) {
+	Mock(func(p *structs.MockProvider) {
+		p.On("InstanceUncordon", "i-1234").Return(nil)
+
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		if assert.Nil(t, hf.Request("DELETE", "/instances/i-1234/cordon", nil)) {
+			hf.AssertCode(t, 200)
+			hf.AssertSuccess(t)
+		}
+	})
+}
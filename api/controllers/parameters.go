@@ -44,6 +44,11 @@ func ParametersSet(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	}
 
 	if app == os.Getenv("RACK") {
+		// every form value is a parameter here so there is no way to force past a pin
+		if err := checkPin(params["Version"], false); err != nil {
+			return err
+		}
+
 		if err := Provider.SystemUpdate(structs.SystemUpdateOptions{Parameters: params}); err != nil {
 			return httperr.Server(err)
 		}
@@ -54,6 +54,8 @@ func NewRouter() (router *mux.Router) {
 	router.HandleFunc("/events/{action}", api("event.send", EventSend)).Methods("POST")
 	router.HandleFunc("/instances", api("instances.get", InstancesList)).Methods("GET")
 	router.HandleFunc("/instances/{id}", api("instance.delete", InstanceTerminate)).Methods("DELETE")
+	router.HandleFunc("/instances/{id}/cordon", api("instance.cordon", InstanceCordon)).Methods("POST")
+	router.HandleFunc("/instances/{id}/cordon", api("instance.uncordon", InstanceUncordon)).Methods("DELETE")
 	router.HandleFunc("/instances/keyroll", api("instances.keyroll", InstancesKeyroll)).Methods("POST")
 	router.HandleFunc("/racks", api("rack.list", RackList)).Methods("GET")
 	router.HandleFunc("/registries", api("registry.list", RegistryList)).Methods("GET")
@@ -70,7 +72,12 @@ func NewRouter() (router *mux.Router) {
 	router.HandleFunc("/sns", SNSConfirm).Methods("POST").Headers("X-Amz-Sns-Message-Type", "SubscriptionConfirmation")
 	router.HandleFunc("/system", api("system.show", SystemShow)).Methods("GET")
 	router.HandleFunc("/system", api("system.update", SystemUpdate)).Methods("PUT")
+	router.HandleFunc("/system/canary", api("system.update.canary", SystemUpdateCanary)).Methods("PUT")
+	router.HandleFunc("/system/pin", api("system.pin", SystemPin)).Methods("PUT")
+	router.HandleFunc("/system/range", api("system.update.range", SystemUpdateRange)).Methods("PUT")
+	router.HandleFunc("/system/verified", api("system.update.verified", SystemUpdateVerified)).Methods("PUT")
 	router.HandleFunc("/system/capacity", api("system.capacity", SystemCapacity)).Methods("GET")
+	router.HandleFunc("/system/parameters/definitions", api("system.parameters.definitions", SystemParameterDefinitions)).Methods("GET")
 	router.HandleFunc("/system/processes", api("system.processes", SystemProcesses)).Methods("GET")
 	router.HandleFunc("/system/releases", api("system.releases", SystemReleases)).Methods("GET")
 	router.HandleFunc("/switch", api("switch", Switch)).Methods("POST")
@@ -16,7 +16,7 @@ import (
 )
 
 func SystemShow(rw http.ResponseWriter, r *http.Request) *httperr.Error {
-	rack, err := Provider.SystemGet()
+	rack, err := systemWithPin()
 	if err != nil {
 		return httperr.Server(err)
 	}
@@ -24,6 +24,66 @@ func SystemShow(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	return RenderJson(rw, rack)
 }
 
+// systemPinSetting holds the version set with `convox rack update --pin`
+const systemPinSetting = "system/pin"
+
+// SystemPin records the version the rack is pinned to, an empty version removes the pin
+func SystemPin(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	if v := GetForm(r, "version"); v != "" {
+		if err := Provider.SettingPut(systemPinSetting, v); err != nil {
+			return httperr.Server(err)
+		}
+	} else {
+		if err := Provider.SettingDelete(systemPinSetting); err != nil {
+			return httperr.Server(err)
+		}
+	}
+
+	rack, err := systemWithPin()
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, rack)
+}
+
+// systemWithPin is the rack with its pinned version, providers without settings are never pinned
+func systemWithPin() (*structs.System, error) {
+	rack, err := Provider.SystemGet()
+	if err != nil {
+		return nil, err
+	}
+
+	if rack == nil {
+		return nil, nil
+	}
+
+	if v, err := Provider.SettingGet(systemPinSetting); err == nil {
+		rack.Pin = v
+	}
+
+	return rack, nil
+}
+
+// checkPin refuses to update the rack past the version it is pinned to unless the update is
+// forced, so the pin holds for every client and not only for `convox rack update`
+func checkPin(version string, force bool) *httperr.Error {
+	if version == "" || force {
+		return nil
+	}
+
+	pin, err := Provider.SettingGet(systemPinSetting)
+	if err != nil || pin == "" {
+		return nil
+	}
+
+	if version > pin {
+		return httperr.Errorf(403, "rack is pinned to %s, unpin it or force the update to go past it", pin)
+	}
+
+	return nil
+}
+
 func SystemProcesses(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	all := r.URL.Query().Get("all")
 
@@ -69,6 +129,10 @@ func SystemUpdate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	}
 
 	if v := GetForm(r, "version"); v != "" {
+		if err := checkPin(v, GetForm(r, "force") == "true"); err != nil {
+			return err
+		}
+
 		opts.Version = options.String(v)
 	}
 
@@ -84,6 +148,122 @@ func SystemUpdate(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	return RenderJson(rw, s)
 }
 
+// SystemUpdateCanary has its own route so that racks without it reject the
+// request instead of silently running a full update
+func SystemUpdateCanary(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	v := GetForm(r, "version")
+	if v == "" {
+		return httperr.Errorf(403, "version required")
+	}
+
+	if err := checkPin(v, GetForm(r, "force") == "true"); err != nil {
+		return err
+	}
+
+	opts := structs.SystemUpdateOptions{
+		Canary:  options.Bool(true),
+		Version: options.String(v),
+	}
+
+	if err := Provider.SystemUpdate(opts); err != nil {
+		return httperr.Server(err)
+	}
+
+	s, err := Provider.SystemGet()
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, s)
+}
+
+// SystemUpdateVerified updates the rack only if the template for version matches the digest the
+// caller verified. It has its own route so that racks that can not check the template reject the
+// request instead of applying whatever template they fetch
+func SystemUpdateVerified(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	v := GetForm(r, "version")
+	if v == "" {
+		return httperr.Errorf(403, "version required")
+	}
+
+	d := GetForm(r, "digest")
+	if d == "" {
+		return httperr.Errorf(403, "digest required")
+	}
+
+	if err := checkPin(v, GetForm(r, "force") == "true"); err != nil {
+		return err
+	}
+
+	opts := structs.SystemUpdateOptions{
+		TemplateDigest: options.String(d),
+		Version:        options.String(v),
+	}
+
+	if err := Provider.SystemUpdate(opts); err != nil {
+		return httperr.Server(err)
+	}
+
+	s, err := Provider.SystemGet()
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, s)
+}
+
+// SystemUpdateRange sets the bounds autoscaling keeps the instance count within. It has its own
+// route so that racks without bounds reject the request instead of ignoring them
+func SystemUpdateRange(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	opts := structs.SystemUpdateOptions{}
+
+	for _, b := range []struct {
+		name  string
+		value **int
+	}{
+		{"min", &opts.InstanceMin},
+		{"max", &opts.InstanceMax},
+	} {
+		v := GetForm(r, b.name)
+		if v == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return httperr.Errorf(403, "%s must be numeric", b.name)
+		}
+
+		switch {
+		case n == -1:
+			// -1 indicates no change
+		case n < structs.SystemRangeMin:
+			return httperr.New(403, structs.SystemRangeError(b.name))
+		default:
+			*b.value = options.Int(n)
+		}
+	}
+
+	if opts.InstanceMin == nil && opts.InstanceMax == nil {
+		return httperr.Errorf(403, "min or max required")
+	}
+
+	if opts.InstanceMin != nil && opts.InstanceMax != nil && *opts.InstanceMin > *opts.InstanceMax {
+		return httperr.Errorf(403, "min must not be greater than max")
+	}
+
+	if err := Provider.SystemUpdate(opts); err != nil {
+		return httperr.Server(err)
+	}
+
+	s, err := Provider.SystemGet()
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, s)
+}
+
 func SystemCapacity(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	capacity, err := Provider.CapacityGet()
 	if err != nil {
@@ -112,11 +292,23 @@ func SystemLogs(ws *websocket.Conn) *httperr.Error {
 		}
 	}
 
-	r, err := Provider.SystemLogs(structs.LogsOptions{
+	opts := structs.LogsOptions{
 		Filter: header.Get("Filter"),
 		Follow: follow,
 		Since:  time.Now().UTC().Add(-1 * since),
-	})
+	}
+
+	if u := header.Get("Until"); u != "" {
+		until, err := time.ParseDuration(u)
+		if err != nil {
+			return httperr.Errorf(403, "Invalid duration %s", u)
+		}
+
+		opts.Follow = false
+		opts.Until = time.Now().UTC().Add(-1 * until)
+	}
+
+	r, err := Provider.SystemLogs(opts)
 	if err != nil {
 		return httperr.Server(err)
 	}
@@ -128,6 +320,16 @@ func SystemLogs(ws *websocket.Conn) *httperr.Error {
 	return nil
 }
 
+// SystemParameterDefinitions describes the parameters of the template the rack runs
+func SystemParameterDefinitions(rw http.ResponseWriter, r *http.Request) *httperr.Error {
+	defs, err := Provider.SystemParameterDefinitions()
+	if err != nil {
+		return httperr.Server(err)
+	}
+
+	return RenderJson(rw, defs)
+}
+
 func SystemReleases(rw http.ResponseWriter, r *http.Request) *httperr.Error {
 	releases, err := Provider.SystemReleases()
 	if err != nil {
@@ -26,6 +26,7 @@ func TestSystemShow(t *testing.T) {
 		}
 
 		p.On("SystemGet").Return(system, nil)
+		p.On("SettingGet", "system/pin").Return("", fmt.Errorf("no such setting: system/pin"))
 
 		hf := test.NewHandlerFunc(controllers.HandlerFunc)
 
@@ -36,6 +37,85 @@ func TestSystemShow(t *testing.T) {
 	})
 }
 
+func TestSystemPin(t *testing.T) {
+	Mock(func(p *structs.MockProvider) {
+		system := &structs.System{
+			Name:    "test",
+			Status:  "running",
+			Version: "dev",
+		}
+
+		p.On("SettingPut", "system/pin", "20170101000000").Return(nil)
+		p.On("SystemGet").Return(system, nil)
+		p.On("SettingGet", "system/pin").Return("20170101000000", nil)
+
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		v := url.Values{}
+		v.Add("version", "20170101000000")
+
+		if assert.Nil(t, hf.Request("PUT", "/system/pin", v)) {
+			hf.AssertCode(t, 200)
+			hf.AssertJSON(t, "{\"count\":0,\"domain\":\"\",\"image\":\"\",\"name\":\"test\",\"pin\":\"20170101000000\",\"provider\":\"\",\"region\":\"\",\"status\":\"running\",\"type\":\"\",\"version\":\"dev\"}")
+		}
+	})
+}
+
+func TestSystemUpdatePinned(t *testing.T) {
+	Mock(func(p *structs.MockProvider) {
+		system := &structs.System{
+			Name:    "test",
+			Status:  "running",
+			Version: "20170101000000",
+		}
+
+		p.On("SettingGet", "system/pin").Return("20170101000000", nil)
+		p.On("SystemUpdate", structs.SystemUpdateOptions{Version: options.String("20170101000000")}).Return(nil)
+		p.On("SystemUpdate", structs.SystemUpdateOptions{Version: options.String("20170201000000")}).Return(nil)
+		p.On("SystemGet").Return(system, nil)
+
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		for _, path := range []string{"/system", "/system/canary", "/system/verified"} {
+			v := url.Values{}
+			v.Add("digest", "abc123")
+			v.Add("version", "20170201000000")
+
+			if assert.Nil(t, hf.Request("PUT", path, v)) {
+				hf.AssertCode(t, 403)
+				hf.AssertError(t, "rack is pinned to 20170101000000, unpin it or force the update to go past it")
+			}
+		}
+
+		v := url.Values{}
+		v.Add("version", "20170101000000")
+
+		if assert.Nil(t, hf.Request("PUT", "/system", v)) {
+			hf.AssertCode(t, 200)
+		}
+
+		v = url.Values{}
+		v.Add("force", "true")
+		v.Add("version", "20170201000000")
+
+		if assert.Nil(t, hf.Request("PUT", "/system", v)) {
+			hf.AssertCode(t, 200)
+		}
+
+		defer os.Setenv("RACK", os.Getenv("RACK"))
+
+		os.Setenv("RACK", "convox-test")
+
+		v = url.Values{}
+		v.Add("Version", "20170201000000")
+
+		if assert.Nil(t, hf.Request("POST", "/apps/convox-test/parameters", v)) {
+			hf.AssertCode(t, 403)
+			hf.AssertError(t, "rack is pinned to 20170101000000, unpin it or force the update to go past it")
+		}
+	})
+}
+
 func TestSystemShowRackFetchError(t *testing.T) {
 	Mock(func(p *structs.MockProvider) {
 		p.On("SystemGet").Return(nil, fmt.Errorf("some error"))
@@ -49,6 +129,36 @@ func TestSystemShowRackFetchError(t *testing.T) {
 	})
 }
 
+func TestSystemParameterDefinitions(t *testing.T) {
+	Mock(func(p *structs.MockProvider) {
+		defs := structs.ParameterDefinitions{
+			"InstanceCount": {Default: "3", Description: "number of instances", MinValue: "3", Type: "Number"},
+		}
+
+		p.On("SystemParameterDefinitions").Return(defs, nil)
+
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		if assert.Nil(t, hf.Request("GET", "/system/parameters/definitions", nil)) {
+			hf.AssertCode(t, 200)
+			hf.AssertJSON(t, `{"InstanceCount":{"AllowedPattern":"","AllowedValues":null,"ConstraintDescription":"","Default":"3","Description":"number of instances","MaxLength":"","MaxValue":"","MinLength":"","MinValue":"3","NoEcho":false,"Type":"Number"}}`)
+		}
+	})
+}
+
+func TestSystemParameterDefinitionsUnsupported(t *testing.T) {
+	Mock(func(p *structs.MockProvider) {
+		p.On("SystemParameterDefinitions").Return(nil, fmt.Errorf("parameter definitions are not supported by this rack"))
+
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		if assert.Nil(t, hf.Request("GET", "/system/parameters/definitions", nil)) {
+			hf.AssertCode(t, 500)
+			hf.AssertError(t, "parameter definitions are not supported by this rack")
+		}
+	})
+}
+
 func TestSystemUpdate(t *testing.T) {
 	Mock(func(p *structs.MockProvider) {
 		before := &structs.System{
@@ -66,6 +176,7 @@ func TestSystemUpdate(t *testing.T) {
 			Version:       options.String("latest"),
 		}
 
+		p.On("SettingGet", "system/pin").Return("", fmt.Errorf("no such key: system/pin"))
 		p.On("SystemUpdate", opts).Return(nil)
 		p.On("SystemGet").Return(before, nil)
 
@@ -83,6 +194,131 @@ func TestSystemUpdate(t *testing.T) {
 	})
 }
 
+func TestSystemUpdateCanary(t *testing.T) {
+	Mock(func(p *structs.MockProvider) {
+		opts := structs.SystemUpdateOptions{
+			Canary:  options.Bool(true),
+			Version: options.String("latest"),
+		}
+
+		p.On("SettingGet", "system/pin").Return("", fmt.Errorf("no such key: system/pin"))
+		p.On("SystemUpdate", opts).Return(fmt.Errorf("canary updates are not supported by this rack"))
+
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		v := url.Values{}
+		v.Add("version", "latest")
+
+		if assert.Nil(t, hf.Request("PUT", "/system/canary", v)) {
+			hf.AssertCode(t, 500)
+			hf.AssertError(t, "canary updates are not supported by this rack")
+		}
+	})
+}
+
+func TestSystemUpdateVerified(t *testing.T) {
+	Mock(func(p *structs.MockProvider) {
+		system := &structs.System{
+			Count:   3,
+			Name:    "test",
+			Status:  "updating",
+			Version: "20180101000000",
+		}
+
+		p.On("SettingGet", "system/pin").Return("", fmt.Errorf("no such key: system/pin"))
+		opts := structs.SystemUpdateOptions{
+			TemplateDigest: options.String("abc123"),
+			Version:        options.String("20180101000000"),
+		}
+
+		p.On("SystemUpdate", opts).Return(nil)
+		p.On("SystemGet").Return(system, nil)
+
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		v := url.Values{}
+		v.Add("version", "20180101000000")
+		v.Add("digest", "abc123")
+
+		if assert.Nil(t, hf.Request("PUT", "/system/verified", v)) {
+			hf.AssertCode(t, 200)
+			hf.AssertJSON(t, `{"count":3,"domain":"","image":"","name":"test","provider":"","region":"","status":"updating","type":"","version":"20180101000000"}`)
+		}
+
+		v = url.Values{}
+		v.Add("version", "20180101000000")
+
+		if assert.Nil(t, hf.Request("PUT", "/system/verified", v)) {
+			hf.AssertCode(t, 403)
+			hf.AssertError(t, "digest required")
+		}
+
+		v = url.Values{}
+		v.Add("digest", "abc123")
+
+		if assert.Nil(t, hf.Request("PUT", "/system/verified", v)) {
+			hf.AssertCode(t, 403)
+			hf.AssertError(t, "version required")
+		}
+	})
+}
+
+func TestSystemUpdateRange(t *testing.T) {
+	Mock(func(p *structs.MockProvider) {
+		system := &structs.System{
+			Count:   3,
+			Name:    "test",
+			Status:  "updating",
+			Version: "dev",
+		}
+
+		opts := structs.SystemUpdateOptions{
+			InstanceMax: options.Int(10),
+			InstanceMin: options.Int(3),
+		}
+
+		p.On("SystemUpdate", opts).Return(nil)
+		p.On("SystemGet").Return(system, nil)
+
+		hf := test.NewHandlerFunc(controllers.HandlerFunc)
+
+		v := url.Values{}
+		v.Add("min", "3")
+		v.Add("max", "10")
+
+		if assert.Nil(t, hf.Request("PUT", "/system/range", v)) {
+			hf.AssertCode(t, 200)
+		}
+
+		v = url.Values{}
+		v.Add("min", "8")
+		v.Add("max", "4")
+
+		if assert.Nil(t, hf.Request("PUT", "/system/range", v)) {
+			hf.AssertCode(t, 403)
+			hf.AssertError(t, "min must not be greater than max")
+		}
+
+		v = url.Values{}
+		v.Add("min", "-1")
+		v.Add("max", "2")
+
+		if assert.Nil(t, hf.Request("PUT", "/system/range", v)) {
+			hf.AssertCode(t, 403)
+			hf.AssertError(t, "max must be at least 3")
+		}
+
+		v = url.Values{}
+		v.Add("min", "2")
+		v.Add("max", "10")
+
+		if assert.Nil(t, hf.Request("PUT", "/system/range", v)) {
+			hf.AssertCode(t, 403)
+			hf.AssertError(t, "min must be at least 3")
+		}
+	})
+}
+
 func TestSystemUpdateAutoscaleCount(t *testing.T) {
 	Mock(func(p *structs.MockProvider) {
 		as := os.Getenv("AUTOSCALE")
@@ -12,6 +12,7 @@ type App struct {
 	Release    string `json:"release"`
 	Sleep      bool   `json:"sleep"`
 	Status     string `json:"status"`
+	Version    string `json:"version,omitempty"`
 }
 
 type Apps []App
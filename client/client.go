@@ -23,6 +23,9 @@ import (
 //this just needs to be random enough to never show up again in a byte stream
 var StatusCodePrefix = "F1E49A85-0AD7-4AEF-A618-C249C6E6568D:"
 
+// streamKeepAlive is the tcp keepalive period for streaming connections
+var streamKeepAlive = 15 * time.Second
+
 type Client struct {
 	Host     string
 	Password string
@@ -374,6 +377,9 @@ func (c *Client) Stream(path string, headers map[string]string, in io.Reader, ou
 		}
 	}
 
+	// keepalives let long quiet streams notice when the connection has died
+	config.Dialer = &net.Dialer{KeepAlive: streamKeepAlive}
+
 	var ws *websocket.Conn
 
 	if proxy := os.Getenv("HTTPS_PROXY"); proxy != "" {
@@ -389,6 +395,7 @@ func (c *Client) Stream(path string, headers map[string]string, in io.Reader, ou
 	defer ws.Close()
 
 	var wg sync.WaitGroup
+	var cerr error
 
 	if in != nil {
 		go io.Copy(ws, in)
@@ -396,11 +403,19 @@ func (c *Client) Stream(path string, headers map[string]string, in io.Reader, ou
 
 	if out != nil {
 		wg.Add(1)
-		go copyAsync(out, ws, &wg)
+		go func() {
+			defer wg.Done()
+			_, cerr = io.Copy(out, ws)
+		}()
 	}
 
 	wg.Wait()
 
+	// a closed stream ends cleanly, a lost connection is an error
+	if ne, ok := cerr.(net.Error); ok {
+		return fmt.Errorf("connection lost: %s", ne)
+	}
+
 	return nil
 }
 
@@ -431,11 +446,6 @@ func (c *Client) client() *http.Client {
 	return client
 }
 
-func copyAsync(dst io.Writer, src io.Reader, wg *sync.WaitGroup) {
-	defer wg.Done()
-	io.Copy(dst, src)
-}
-
 // Request wraps http.Request and sets some Convox-specific headers
 func (c *Client) Request(method, path string, body io.Reader) (*http.Request, error) {
 	req, err := http.NewRequest(method, fmt.Sprintf("https://%s%s", c.Host, path), body)
@@ -470,7 +480,9 @@ func (c *Client) proxyWebsocket(config *websocket.Config, proxy string) (*websoc
 		host += ":443"
 	}
 
-	conn, err := net.DialTimeout("tcp", u.Host, 3*time.Second)
+	dialer := &net.Dialer{KeepAlive: streamKeepAlive, Timeout: 3 * time.Second}
+
+	conn, err := dialer.Dial("tcp", u.Host)
 
 	if err != nil {
 		return nil, err
@@ -11,6 +11,18 @@ type Error struct {
 	Error string `json:"error"`
 }
 
+// AuthError is returned when the rack rejects the password
+type AuthError string
+
+func (e AuthError) Error() string {
+	return string(e)
+}
+
+// Unauthorized lets callers recognize the error without depending on this package
+func (e AuthError) Unauthorized() bool {
+	return true
+}
+
 func responseError(res *http.Response) error {
 	if res.StatusCode < 400 {
 		return nil
@@ -27,6 +39,11 @@ func responseError(res *http.Response) error {
 	err = json.Unmarshal(data, &e)
 
 	if err != nil {
+		// the rack answers a bad password with a plain text 401, json errors come from the request itself
+		if res.StatusCode == 401 {
+			return AuthError(fmt.Sprintf("response status: %d %s", res.StatusCode, data))
+		}
+
 		return fmt.Errorf("response status: %d %s", res.StatusCode, data)
 	}
 
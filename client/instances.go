@@ -10,6 +10,7 @@ import (
 
 type Instance struct {
 	Agent     bool      `json:"agent"`
+	Cordoned  bool      `json:"cordoned"`
 	Cpu       float64   `json:"cpu"`
 	Id        string    `json:"id"`
 	Memory    float64   `json:"memory"`
@@ -18,6 +19,8 @@ type Instance struct {
 	PublicIp  string    `json:"public-ip"`
 	Status    string    `json:"status"`
 	Started   time.Time `json:"started"`
+	Type      string    `json:"type,omitempty"`
+	Zone      string    `json:"zone,omitempty"`
 }
 
 func (c *Client) GetInstances() ([]*Instance, error) {
@@ -32,6 +35,21 @@ func (c *Client) GetInstances() ([]*Instance, error) {
 	return instances, nil
 }
 
+// CordonInstance stops new processes from being placed on an instance
+func (c *Client) CordonInstance(id string) error {
+	var response map[string]interface{}
+
+	if err := c.Post(fmt.Sprintf("/instances/%s/cordon", id), nil, &response); err != nil {
+		return err
+	}
+
+	if response["success"] == nil {
+		return errors.New(response["error"].(string))
+	}
+
+	return nil
+}
+
 func (c *Client) InstanceKeyroll() error {
 	var response map[string]interface{}
 	err := c.Post("/instances/keyroll", nil, &response)
@@ -92,3 +110,18 @@ func (c *Client) TerminateInstance(id string) error {
 
 	return nil
 }
+
+// UncordonInstance allows new processes to be placed on an instance again
+func (c *Client) UncordonInstance(id string) error {
+	var response map[string]interface{}
+
+	if err := c.Delete(fmt.Sprintf("/instances/%s/cordon", id), &response); err != nil {
+		return err
+	}
+
+	if response["success"] == nil {
+		return errors.New(response["error"].(string))
+	}
+
+	return nil
+}
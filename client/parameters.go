@@ -2,8 +2,28 @@ package client
 
 import "fmt"
 
+// RackTemplate is the location of the published rack template for a given version
+var RackTemplate = "https://convox.s3.amazonaws.com/release/%s/rack.json"
+
 type Parameters map[string]string
 
+// ParameterDefinition describes a parameter as declared in the rack template
+type ParameterDefinition struct {
+	AllowedPattern        string   `json:"AllowedPattern"`
+	AllowedValues         []string `json:"AllowedValues"`
+	ConstraintDescription string   `json:"ConstraintDescription"`
+	Default               string   `json:"Default"`
+	Description           string   `json:"Description"`
+	MaxLength             string   `json:"MaxLength"`
+	MaxValue              string   `json:"MaxValue"`
+	MinLength             string   `json:"MinLength"`
+	MinValue              string   `json:"MinValue"`
+	NoEcho                bool     `json:"NoEcho"`
+	Type                  string   `json:"Type"`
+}
+
+type ParameterDefinitions map[string]ParameterDefinition
+
 func (c *Client) ListParameters(app string) (Parameters, error) {
 	var formation Parameters
 
@@ -16,6 +36,17 @@ func (c *Client) ListParameters(app string) (Parameters, error) {
 	return formation, nil
 }
 
+// ListParameterDefinitions describes the parameters of the template the rack runs
+func (c *Client) ListParameterDefinitions() (ParameterDefinitions, error) {
+	var defs ParameterDefinitions
+
+	if err := c.Get("/system/parameters/definitions", &defs); err != nil {
+		return nil, err
+	}
+
+	return defs, nil
+}
+
 func (c *Client) SetParameters(app string, params map[string]string) error {
 	var success interface{}
 	return c.Post(fmt.Sprintf("/apps/%s/parameters", app), params, &success)
@@ -3,6 +3,7 @@ package client
 import (
 	"fmt"
 	"io"
+	"strings"
 	"time"
 )
 
@@ -19,11 +20,43 @@ func (c *Client) Racks() (racks []Rack, err error) {
 	return racks, err
 }
 
-// StreamRackLogs streams the logs for a Rack
-func (c *Client) StreamRackLogs(filter string, follow bool, since time.Duration, output io.WriteCloser) error {
-	return c.Stream("/system/logs", map[string]string{
-		"Filter": filter,
+// StreamRackLogs streams the rack logs from since ago, up to until ago when until is not 0. Only
+// lines matching every one of filters are streamed, no filters matches everything
+func (c *Client) StreamRackLogs(filters []string, follow bool, since, until time.Duration, output io.WriteCloser) error {
+	headers := map[string]string{
+		"Filter": logFilterPattern(filters),
 		"Follow": fmt.Sprintf("%t", follow),
 		"Since":  since.String(),
-	}, nil, output)
+	}
+
+	if until > 0 {
+		headers["Until"] = until.String()
+	}
+
+	return c.Stream("/system/logs", headers, nil, output)
+}
+
+// logFilterPattern joins filter tokens into one pattern that matches lines containing all of them.
+// A single filter is passed through as is so it can still be a full pattern, with several any
+// token containing spaces is quoted to keep it together
+func logFilterPattern(filters []string) string {
+	if len(filters) == 1 {
+		return filters[0]
+	}
+
+	terms := []string{}
+
+	for _, f := range filters {
+		if f == "" {
+			continue
+		}
+
+		if strings.ContainsAny(f, " \t") && !strings.HasPrefix(f, `"`) {
+			f = fmt.Sprintf("%q", f)
+		}
+
+		terms = append(terms, f)
+	}
+
+	return strings.Join(terms, " ")
 }
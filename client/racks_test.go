@@ -0,0 +1,15 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogFilterPattern(t *testing.T) {
+	assert.Equal(t, "", logFilterPattern(nil))
+	assert.Equal(t, "connection refused", logFilterPattern([]string{"connection refused"}))
+	assert.Equal(t, "ERROR web", logFilterPattern([]string{"ERROR", "web"}))
+	assert.Equal(t, `ERROR "connection refused"`, logFilterPattern([]string{"ERROR", "", "connection refused"}))
+	assert.Equal(t, `ERROR "already quoted"`, logFilterPattern([]string{"ERROR", `"already quoted"`}))
+}
@@ -3,18 +3,23 @@ package client
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/convox/rack/structs"
 )
 
 type System struct {
-	Count   int    `json:"count"`
-	Domain  string `json:"domain"`
-	Name    string `json:"name"`
-	Region  string `json:"region"`
-	Status  string `json:"status"`
-	Type    string `json:"type"`
-	Version string `json:"version"`
+	Count      int               `json:"count"`
+	Domain     string            `json:"domain"`
+	Name       string            `json:"name"`
+	Outputs    map[string]string `json:"outputs,omitempty"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+	Pin        string            `json:"pin,omitempty"`
+	Provider   string            `json:"provider"`
+	Region     string            `json:"region"`
+	Status     string            `json:"status"`
+	Type       string            `json:"type"`
+	Version    string            `json:"version"`
 }
 
 type SystemCapacity struct {
@@ -54,6 +59,17 @@ func (c *Client) GetSystem() (*System, error) {
 	return &system, nil
 }
 
+// AvailabilityZones returns the number of availability zones the rack spans, or 0 if unknown
+func (s *System) AvailabilityZones() int {
+	subnets := s.Outputs["Subnets"]
+
+	if subnets == "" {
+		return 0
+	}
+
+	return len(strings.Split(subnets, ","))
+}
+
 func (c *Client) GetSystemCapacity() (*SystemCapacity, error) {
 	var capacity SystemCapacity
 
@@ -95,7 +111,8 @@ func (c *Client) GetSystemReleases() (Releases, error) {
 	return releases, nil
 }
 
-func (c *Client) UpdateSystem(version string) (*System, error) {
+// UpdateSystem updates the rack to version, force updates it past the version it is pinned to
+func (c *Client) UpdateSystem(version string, force bool) (*System, error) {
 	var system System
 
 	err := c.Get("/system", &system)
@@ -112,6 +129,10 @@ func (c *Client) UpdateSystem(version string) (*System, error) {
 		"version": version,
 	}
 
+	if force {
+		params["force"] = "true"
+	}
+
 	err = c.Put("/system", params, &system)
 
 	if err != nil {
@@ -121,6 +142,85 @@ func (c *Client) UpdateSystem(version string) (*System, error) {
 	return &system, nil
 }
 
+// UpdateSystemCanary updates a subset of instances first and only continues once they are healthy
+func (c *Client) UpdateSystemCanary(version string, force bool) (*System, error) {
+	var system System
+
+	params := Params{"version": version}
+
+	if force {
+		params["force"] = "true"
+	}
+
+	err := c.Put("/system/canary", params, &system)
+	if err != nil && strings.HasPrefix(err.Error(), "response status: 404") {
+		return nil, fmt.Errorf("canary updates are not supported by this rack")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &system, nil
+}
+
+// UpdateSystemVerified updates the rack to version only if the template it applies has the
+// sha256 digest of the template the caller verified
+func (c *Client) UpdateSystemVerified(version, digest string, force bool) (*System, error) {
+	var system System
+
+	params := Params{"digest": digest, "version": version}
+
+	if force {
+		params["force"] = "true"
+	}
+
+	err := c.Put("/system/verified", params, &system)
+	if err != nil && strings.HasPrefix(err.Error(), "response status: 404") {
+		return nil, fmt.Errorf("verified updates are not supported by this rack")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &system, nil
+}
+
+// PinSystem records the version a rack should stay on, an empty version removes the pin
+func (c *Client) PinSystem(version string) (*System, error) {
+	var system System
+
+	err := c.Put("/system/pin", Params{"version": version}, &system)
+	if err != nil && strings.HasPrefix(err.Error(), "response status: 404") {
+		return nil, fmt.Errorf("pinning is not supported by this rack")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &system, nil
+}
+
+// ScaleSystemRange sets the smallest and largest instance count autoscaling can scale the rack to,
+// -1 leaves a bound unchanged
+func (c *Client) ScaleSystemRange(min, max int) (*System, error) {
+	var system System
+
+	params := Params{
+		"min": strconv.Itoa(min),
+		"max": strconv.Itoa(max),
+	}
+
+	err := c.Put("/system/range", params, &system)
+	if err != nil && strings.HasPrefix(err.Error(), "response status: 404") {
+		return nil, fmt.Errorf("autoscaling bounds are not supported by this rack")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &system, nil
+}
+
 func (c *Client) UpdateSystemOriginal(version string) (*System, error) {
 	err := c.Post("/system", map[string]string{"version": version}, nil)
 
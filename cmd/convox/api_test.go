@@ -105,7 +105,7 @@ func TestApiHelpFlag(t *testing.T) {
 			Command:    "convox api get",
 			OutMatches: apiGetUsages,
 			Stderr:     apiMissingEndpoint,
-			Exit:       129,
+			Exit:       2,
 		},
 		test.ExecRun{
 			Command:    "convox api get h",
@@ -143,14 +143,14 @@ func TestApiHelpFlag(t *testing.T) {
 			Command:    "convox api get foo bar",
 			Env:        DebuglessEnv,
 			OutMatches: apiGetUsages,
-			Exit:       129,
+			Exit:       2,
 		},
 		test.ExecRun{
 			Command:    "convox api get foo bar",
 			Env:        DebugfulEnv,
 			Stderr:     "ERROR: expected 1 argument <endpoint>; got 2 arguments (foo bar).\n",
 			OutMatches: apiGetUsages,
-			Exit:       129,
+			Exit:       2,
 		},
 	}
 
@@ -268,7 +268,7 @@ func TestApiGetNoArg(t *testing.T) {
 	test.Runs(t,
 		test.ExecRun{
 			Command:    "convox api get",
-			Exit:       129,
+			Exit:       2,
 			OutMatches: apiGetUsages,
 		},
 	)
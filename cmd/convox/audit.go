@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/convox/rack/cmd/convox/helpers"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var recordFlag = cli.StringFlag{
+	Name:  "record",
+	Usage: "record a note explaining why this change was made",
+}
+
+// auditEntry is an operator note recorded alongside a rack change. Racks have
+// nowhere to store these so they are kept locally, one json entry per line.
+type auditEntry struct {
+	Changes map[string]string `json:"changes"`
+	Message string            `json:"message"`
+	Rack    string            `json:"rack"`
+	Time    time.Time         `json:"time"`
+}
+
+func auditFile(rack string) string {
+	return filepath.Join(ConfigRoot, "audit", fmt.Sprintf("%s.jsonl", strings.Replace(rack, "/", "-", -1)))
+}
+
+// recordAudit saves the --record note for a change, if one was given
+func recordAudit(c *cli.Context, changes map[string]string) error {
+	message := c.String("record")
+	if message == "" {
+		return nil
+	}
+
+	rack := helpers.Coalesce(currentRack(c), "default")
+
+	data, err := json.Marshal(auditEntry{Changes: changes, Message: message, Rack: rack, Time: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+
+	file := auditFile(rack)
+
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+
+	fd, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	defer fd.Close()
+
+	_, err = fmt.Fprintln(fd, string(data))
+	return err
+}
+
+// readAudit returns the recorded notes for a rack, oldest first
+func readAudit(rack string) ([]auditEntry, error) {
+	fd, err := os.Open(auditFile(rack))
+	if os.IsNotExist(err) {
+		return []auditEntry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	defer fd.Close()
+
+	entries := []auditEntry{}
+
+	s := bufio.NewScanner(fd)
+
+	for s.Scan() {
+		var e auditEntry
+
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			continue
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, s.Err()
+}
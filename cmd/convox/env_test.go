@@ -73,7 +73,7 @@ func TestGetEnvNoVariableSpecified(t *testing.T) {
 	test.Runs(t,
 		test.ExecRun{
 			Command: "convox env get",
-			Exit:    129,
+			Exit:    2,
 			Stderr:  "ERROR: 1 argument is required: VARIABLE",
 		},
 	)
@@ -7,13 +7,41 @@ var appFlag = cli.StringFlag{
 	Usage: "app name inferred from current directory if not specified",
 }
 
+var configFlag = cli.StringFlag{
+	Name:  "config",
+	Usage: "rack config file to use instead of racks.json, or a directory to use instead of ~/.convox like CONVOX_CONFIG",
+}
+
 var rackFlag = cli.StringFlag{
 	Name:  "rack",
 	Usage: "rack name",
 }
 
+var urlFlag = cli.StringFlag{
+	Name:   "url",
+	EnvVar: "CONVOX_RACK_URL",
+	Usage:  "rack url with embedded credentials, takes precedence over any login",
+}
+
 var waitFlag = cli.BoolFlag{
 	Name:   "wait",
 	EnvVar: "CONVOX_WAIT",
 	Usage:  "wait for change to finish before returning",
 }
+
+var outputFlag = cli.StringFlag{
+	Name:   "output",
+	EnvVar: "CONVOX_OUTPUT",
+	Usage:  "output format (text or json), errors are written as json too",
+	Value:  "text",
+}
+
+var showSecretsFlag = cli.BoolFlag{
+	Name:  "show-secrets",
+	Usage: "show the values of sensitive parameters instead of ****",
+}
+
+var noColorFlag = cli.BoolFlag{
+	Name:  "no-color",
+	Usage: "do not color the output even on a terminal",
+}
@@ -44,7 +44,9 @@ Commands: (convox <command> --help)
   
 Options:
   --app value, -a value  app name inferred from current directory if not specified
+  --config value         rack config file to use instead of racks.json, or a directory to use instead of ~/.convox like CONVOX_CONFIG
   --rack value           rack name
+  --url value            rack url with embedded credentials, takes precedence over any login [$CONVOX_RACK_URL]
   --help, -h             show help
   --version, -v          print the version
   `
@@ -291,6 +291,11 @@ func cmdInstall(c *cli.Context) error {
 	versionName := version.Version
 	furl := fmt.Sprintf(formationURL, versionName)
 
+	if _, err := verifyTemplateSignature(c, furl); err != nil {
+		stdcli.QOSEventSend("cli-install", distinctID, stdcli.QOSEventProperties{Error: fmt.Errorf("error verifying template: %s", err)})
+		return stdcli.Error(err)
+	}
+
 	fmt.Println(Banner)
 
 	if err != nil {
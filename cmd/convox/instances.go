@@ -1,13 +1,18 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"golang.org/x/crypto/ssh/terminal"
 
+	"github.com/convox/rack/client"
 	"github.com/convox/rack/cmd/convox/helpers"
 	"github.com/convox/rack/cmd/convox/stdcli"
 	"gopkg.in/urfave/cli.v1"
@@ -20,8 +25,16 @@ func init() {
 		Usage:       "[subcommand] [args] [options]",
 		ArgsUsage:   "",
 		Action:      cmdInstancesList,
-		Flags:       []cli.Flag{rackFlag},
+		Flags:       []cli.Flag{rackFlag, outputFlag},
 		Subcommands: []cli.Command{
+			{
+				Name:        "cordon",
+				Description: "stop new processes from being placed on an instance",
+				Usage:       "<instance id> [options]",
+				ArgsUsage:   "<instance id>",
+				Action:      cmdInstancesCordon,
+				Flags:       []cli.Flag{rackFlag},
+			},
 			{
 				Name:        "keyroll",
 				Description: "generate and replace the ec2 keypair used for SSH",
@@ -36,7 +49,12 @@ func init() {
 				Usage:       "<instance id> [command] [options]",
 				ArgsUsage:   "<intance id>",
 				Action:      cmdInstancesSSH,
-				Flags:       []cli.Flag{rackFlag},
+				Flags: []cli.Flag{rackFlag,
+					cli.StringFlag{
+						Name:  "via",
+						Usage: "connect with ssh through the rack or with aws ssm session manager, chosen from the instance if not specified (ssh or ssm)",
+					},
+				},
 			},
 			{
 				Name:        "terminate",
@@ -46,6 +64,14 @@ func init() {
 				Flags:       []cli.Flag{rackFlag},
 				Action:      cmdInstancesTerminate,
 			},
+			{
+				Name:        "uncordon",
+				Description: "allow new processes to be placed on an instance again",
+				Usage:       "<instance id> [options]",
+				ArgsUsage:   "<instance id>",
+				Action:      cmdInstancesUncordon,
+				Flags:       []cli.Flag{rackFlag},
+			},
 		},
 	})
 }
@@ -59,6 +85,35 @@ func cmdInstancesList(c *cli.Context) error {
 		return stdcli.Error(err)
 	}
 
+	format, err := outputFormat(c)
+	if err != nil {
+		return stdcli.Error(err)
+	}
+
+	if format == "json" {
+		infos := []instanceInfo{}
+
+		for _, i := range instances {
+			infos = append(infos, instanceInfo{
+				Agent:     i.Agent,
+				Cordoned:  i.Cordoned,
+				Cpu:       i.Cpu,
+				Healthy:   instanceHealthy(i),
+				Id:        i.Id,
+				Memory:    i.Memory,
+				PrivateIp: i.PrivateIp,
+				Processes: i.Processes,
+				PublicIp:  i.PublicIp,
+				Started:   i.Started,
+				Status:    i.Status,
+				Type:      i.Type,
+				Zone:      i.Zone,
+			})
+		}
+
+		return writeJSON(infos)
+	}
+
 	t := stdcli.NewTable("ID", "AGENT", "STATUS", "STARTED", "PS", "CPU", "MEM", "PUBLIC", "PRIVATE")
 
 	for _, i := range instances {
@@ -67,7 +122,12 @@ func cmdInstancesList(c *cli.Context) error {
 			agent = "on"
 		}
 
-		t.AddRow(i.Id, agent, i.Status,
+		status := i.Status
+		if i.Cordoned {
+			status = fmt.Sprintf("%s (cordoned)", status)
+		}
+
+		t.AddRow(i.Id, agent, status,
 			helpers.HumanizeTime(i.Started),
 			strconv.Itoa(i.Processes),
 			fmt.Sprintf("%0.2f%%", i.Cpu*100),
@@ -80,6 +140,50 @@ func cmdInstancesList(c *cli.Context) error {
 	return nil
 }
 
+// instanceInfo is an instance as written by `convox instances --output json`, cpu and memory
+// are the fraction of the instance's reserved capacity in use
+type instanceInfo struct {
+	Agent     bool      `json:"agent"`
+	Cordoned  bool      `json:"cordoned"`
+	Cpu       float64   `json:"cpu"`
+	Healthy   bool      `json:"healthy"`
+	Id        string    `json:"id"`
+	Memory    float64   `json:"memory"`
+	PrivateIp string    `json:"private-ip"`
+	Processes int       `json:"processes"`
+	PublicIp  string    `json:"public-ip"`
+	Started   time.Time `json:"started"`
+	Status    string    `json:"status"`
+	Type      string    `json:"type,omitempty"`
+	Zone      string    `json:"zone,omitempty"`
+}
+
+// instanceHealthy is true when an instance can run new processes: its agent is connected, it
+// is active and it has not been cordoned
+func instanceHealthy(i *client.Instance) bool {
+	return i.Agent && i.Status == "active" && !i.Cordoned
+}
+
+func cmdInstancesCordon(c *cli.Context) error {
+	stdcli.NeedHelp(c)
+	stdcli.NeedArg(c, 1)
+
+	id := c.Args()[0]
+
+	fmt.Printf("Cordoning %s... ", id)
+
+	if err := rackClient(c).CordonInstance(id); err != nil {
+		return stdcli.Error(err)
+	}
+
+	fmt.Println("OK")
+
+	// services only pick up the placement constraint from the app template
+	fmt.Println("Services of apps deployed before this rack supported cordoning keep being placed here until they are redeployed")
+
+	return nil
+}
+
 func cmdInstancesKeyroll(c *cli.Context) error {
 	stdcli.NeedHelp(c)
 	stdcli.NeedArg(c, 0)
@@ -113,6 +217,22 @@ func cmdInstancesTerminate(c *cli.Context) error {
 	return nil
 }
 
+func cmdInstancesUncordon(c *cli.Context) error {
+	stdcli.NeedHelp(c)
+	stdcli.NeedArg(c, 1)
+
+	id := c.Args()[0]
+
+	fmt.Printf("Uncordoning %s... ", id)
+
+	if err := rackClient(c).UncordonInstance(id); err != nil {
+		return stdcli.Error(err)
+	}
+
+	fmt.Println("OK")
+	return nil
+}
+
 func cmdInstancesSSH(c *cli.Context) error {
 	stdcli.NeedHelp(c)
 	stdcli.NeedArg(c, -1)
@@ -120,6 +240,35 @@ func cmdInstancesSSH(c *cli.Context) error {
 	id := c.Args()[0]
 	cmd := strings.Join(c.Args()[1:], " ")
 
+	via, err := instanceConnectVia(c, id)
+	if err != nil {
+		return stdcli.Error(err)
+	}
+
+	if via == "ssm" {
+		system, err := rackClient(c).GetSystem()
+		if err != nil {
+			return stdcli.Error(err)
+		}
+
+		ssm := exec.Command("aws", ssmArgs(id, system.Region, cmd)...)
+
+		ssm.Stdin = os.Stdin
+		ssm.Stdout = os.Stdout
+		ssm.Stderr = os.Stderr
+
+		if err := ssm.Run(); err != nil {
+			if ee, ok := err.(*exec.ExitError); ok {
+				if ws, ok := ee.Sys().(syscall.WaitStatus); ok {
+					return cli.NewExitError("", ws.ExitStatus())
+				}
+			}
+			return stdcli.Error(err)
+		}
+
+		return nil
+	}
+
 	code, err := sshWithRestore(c, id, cmd)
 	if err != nil {
 		return stdcli.Error(err)
@@ -128,6 +277,54 @@ func cmdInstancesSSH(c *cli.Context) error {
 	return cli.NewExitError("", code)
 }
 
+// instanceConnectVia picks how to reach an instance, instances without a public ip
+// use session manager when the aws cli is available
+func instanceConnectVia(c *cli.Context, id string) (string, error) {
+	switch via := c.String("via"); via {
+	case "ssh", "ssm":
+		return via, nil
+	case "":
+	default:
+		return "", fmt.Errorf("--via must be ssh or ssm")
+	}
+
+	if _, err := exec.LookPath("aws"); err != nil {
+		return "ssh", nil
+	}
+
+	instances, err := rackClient(c).GetInstances()
+	if err != nil {
+		return "", err
+	}
+
+	for _, i := range instances {
+		if i.Id == id {
+			if i.PublicIp == "" {
+				return "ssm", nil
+			}
+			return "ssh", nil
+		}
+	}
+
+	return "", fmt.Errorf("no such instance: %s", id)
+}
+
+// ssmArgs builds the aws cli arguments for a session manager session, running cmd if given
+func ssmArgs(id, region, cmd string) []string {
+	args := []string{"ssm", "start-session", "--target", id}
+
+	if region != "" {
+		args = append(args, "--region", region)
+	}
+
+	if cmd != "" {
+		data, _ := json.Marshal(map[string][]string{"command": {cmd}})
+		args = append(args, "--document-name", "AWS-StartInteractiveCommand", "--parameters", string(data))
+	}
+
+	return args
+}
+
 func sshWithRestore(c *cli.Context, id, cmd string) (int, error) {
 	fd := os.Stdin.Fd()
 	isTerm := terminal.IsTerminal(int(fd))
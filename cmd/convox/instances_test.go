@@ -0,0 +1,96 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstancesSSMArgs(t *testing.T) {
+	assert.Equal(t, []string{"ssm", "start-session", "--target", "i-1234", "--region", "us-east-1"}, ssmArgs("i-1234", "us-east-1", ""))
+	assert.Equal(t, []string{"ssm", "start-session", "--target", "i-1234", "--document-name", "AWS-StartInteractiveCommand", "--parameters", `{"command":["docker ps -a"]}`}, ssmArgs("i-1234", "", "docker ps -a"))
+}
+
+func TestInstancesJSON(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/instances", Code: 200, Response: []client.Instance{
+			{Agent: true, Cpu: 0.25, Id: "i-1", Memory: 0.5, Processes: 2, Started: time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC), Status: "active", Type: "t2.small", Zone: "us-east-1a"},
+			{Agent: true, Cordoned: true, Id: "i-2", Started: time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC), Status: "active"},
+		}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox instances --output json",
+			Exit:    0,
+			Stdout: `[
+  {
+    "agent": true,
+    "cordoned": false,
+    "cpu": 0.25,
+    "healthy": true,
+    "id": "i-1",
+    "memory": 0.5,
+    "private-ip": "",
+    "processes": 2,
+    "public-ip": "",
+    "started": "2018-01-02T03:04:05Z",
+    "status": "active",
+    "type": "t2.small",
+    "zone": "us-east-1a"
+  },
+  {
+    "agent": true,
+    "cordoned": true,
+    "cpu": 0,
+    "healthy": false,
+    "id": "i-2",
+    "memory": 0,
+    "private-ip": "",
+    "processes": 0,
+    "public-ip": "",
+    "started": "2018-01-02T03:04:05Z",
+    "status": "active"
+  }
+]
+`,
+		},
+	)
+}
+
+func TestInstancesCordon(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "POST", Path: "/instances/i-1/cordon", Code: 200, Response: map[string]bool{"success": true}},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox instances cordon i-1",
+			Exit:    0,
+			Stdout:  "Cordoning i-1... OK\nServices of apps deployed before this rack supported cordoning keep being placed here until they are redeployed\n",
+		},
+	)
+}
+
+func TestInstancesJSONEmpty(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/instances", Code: 200, Response: nil},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox instances --output json",
+			Exit:    0,
+			Stdout:  "[]\n",
+		},
+	)
+}
@@ -0,0 +1,942 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/convox/rack/client"
+	"github.com/itchyny/gojq"
+)
+
+// logFilter transforms a single log line, returning false to drop it
+type logFilter func(line string) (string, bool)
+
+// logWriter buffers streamed log output and passes each complete line through its filters
+type logWriter struct {
+	buf     []byte
+	filters []logFilter
+	last    time.Time
+	lock    sync.Mutex
+	out     io.Writer
+}
+
+func newLogWriter(out io.Writer, filters ...logFilter) *logWriter {
+	return &logWriter{filters: filters, last: time.Now(), out: out}
+}
+
+func (w *logWriter) Write(data []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.last = time.Now()
+	w.buf = append(w.buf, data...)
+
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		line := string(w.buf[0:i])
+		w.buf = w.buf[i+1:]
+
+		if err := w.writeLine(line); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(data), nil
+}
+
+// Close flushes any trailing partial line
+func (w *logWriter) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	line := string(w.buf)
+	w.buf = nil
+
+	return w.writeLine(line)
+}
+
+// idle is how long it has been since any data arrived
+func (w *logWriter) idle() time.Duration {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return time.Since(w.last)
+}
+
+// heartbeat writes a line to out whenever w has been idle for every, until the returned func is called
+func (w *logWriter) heartbeat(out io.Writer, every time.Duration, color bool) func() {
+	done := make(chan bool)
+
+	go func() {
+		tick := time.NewTicker(every / 4)
+		defer tick.Stop()
+
+		beat := time.Now()
+
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-tick.C:
+				if w.idle() < every || now.Sub(beat) < every {
+					continue
+				}
+
+				beat = now
+
+				line := fmt.Sprintf("%s still connected, no new logs", now.Format(time.RFC3339))
+
+				if color {
+					line = fmt.Sprintf("\033[2m%s\033[0m", line)
+				}
+
+				fmt.Fprintln(out, line)
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+func (w *logWriter) writeLine(line string) error {
+	for _, f := range w.filters {
+		l, ok := f(line)
+		if !ok {
+			return nil
+		}
+		line = l
+	}
+
+	_, err := fmt.Fprintln(w.out, line)
+	return err
+}
+
+var logLevels = []string{"debug", "info", "warn", "error", "fatal"}
+
+var logLevelAliases = map[string]string{
+	"crit":     "fatal",
+	"critical": "fatal",
+	"err":      "error",
+	"panic":    "fatal",
+	"warning":  "warn",
+}
+
+var logLevelPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\blevel=["']?(\w+)`),
+	regexp.MustCompile(`(?i)"level"\s*:\s*"(\w+)"`),
+	regexp.MustCompile(`(?i)\[(debug|info|warn|warning|error|err|fatal|panic|crit|critical)\]`),
+	regexp.MustCompile(`\b(DEBUG|INFO|WARN|WARNING|ERROR|ERR|FATAL|PANIC|CRIT|CRITICAL)\b`),
+}
+
+// logLevel detects the severity of a log line, returning an empty string if it has none
+func logLevel(line string) string {
+	for _, p := range logLevelPatterns {
+		m := p.FindStringSubmatch(line)
+		if len(m) < 2 {
+			continue
+		}
+
+		level := strings.ToLower(m[1])
+
+		if alias, ok := logLevelAliases[level]; ok {
+			level = alias
+		}
+
+		for _, l := range logLevels {
+			if l == level {
+				return level
+			}
+		}
+	}
+
+	return ""
+}
+
+// parseLogLevels validates a comma separated list of levels
+func parseLogLevels(s string) ([]string, error) {
+	levels := []string{}
+
+	for _, l := range strings.Split(s, ",") {
+		level := strings.ToLower(strings.TrimSpace(l))
+
+		if alias, ok := logLevelAliases[level]; ok {
+			level = alias
+		}
+
+		valid := false
+
+		for _, ll := range logLevels {
+			if ll == level {
+				valid = true
+				break
+			}
+		}
+
+		if !valid {
+			return nil, fmt.Errorf("unknown log level: %s (expected one of: %s)", l, strings.Join(logLevels, ", "))
+		}
+
+		levels = append(levels, level)
+	}
+
+	return levels, nil
+}
+
+// logLevelFilter only passes lines with one of the given levels, lines without a level are dropped
+func logLevelFilter(levels []string) logFilter {
+	return func(line string) (string, bool) {
+		level := logLevel(line)
+
+		for _, l := range levels {
+			if l == level {
+				return line, true
+			}
+		}
+
+		return line, false
+	}
+}
+
+var logLevelColors = map[string]int{
+	"error": 203,
+	"fatal": 196,
+	"warn":  208,
+}
+
+// logLevelColorizer highlights lines according to their level
+func logLevelColorizer(line string) (string, bool) {
+	if color, ok := logLevelColors[logLevel(line)]; ok {
+		return fmt.Sprintf("\033[38;5;%dm%s\033[0m", color, line), true
+	}
+
+	return line, true
+}
+
+// logANSIPattern matches terminal escape sequences: CSI sequences like colors and cursor movement,
+// OSC sequences like window titles and links, and the remaining two character escapes
+var logANSIPattern = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)|\x1b[0-Z\\-_]`)
+
+// logStripANSI removes escape sequences written by the processes themselves
+func logStripANSI(line string) (string, bool) {
+	return logANSIPattern.ReplaceAllString(line, ""), true
+}
+
+// logMatchHook runs a command whenever a line matches, at most once per cooldown. The command
+// runs in the background so a slow one never holds up the stream
+type logMatchHook struct {
+	cooldown time.Duration
+	last     time.Time
+	pattern  *regexp.Regexp
+	run      func(line string)
+}
+
+func newLogMatchHook(pattern *regexp.Regexp, command string, cooldown time.Duration) *logMatchHook {
+	return &logMatchHook{
+		cooldown: cooldown,
+		pattern:  pattern,
+		run:      func(line string) { runLogMatchCommand(command, line) },
+	}
+}
+
+func (h *logMatchHook) filter(line string) (string, bool) {
+	if h.pattern.MatchString(line) && time.Since(h.last) >= h.cooldown {
+		h.last = time.Now()
+		go h.run(line)
+	}
+
+	return line, true
+}
+
+// runLogMatchCommand runs command through the shell with the matching line on stdin, the line
+// is never part of the command itself so log content can not inject shell syntax
+func runLogMatchCommand(command, line string) {
+	cmd := exec.Command("sh", "-c", command)
+
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	}
+
+	cmd.Stdin = strings.NewReader(line + "\n")
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "on-match command failed: %s\n", err)
+	}
+}
+
+// logMarkerFilter only passes lines after the first line matching after and before the first
+// line matching before, either may be nil to leave that end open
+func logMarkerFilter(after, before *regexp.Regexp) logFilter {
+	started := after == nil
+	stopped := false
+
+	return func(line string) (string, bool) {
+		switch {
+		case stopped:
+			return line, false
+		case !started:
+			started = after.MatchString(line)
+			return line, false
+		case before != nil && before.MatchString(line):
+			stopped = true
+			return line, false
+		}
+
+		return line, true
+	}
+}
+
+// logJQFilter applies a jq expression to the json in each line and passes only its results,
+// one per line with strings written raw. Lines without json are passed through unless dropOther
+// is set and lines where the expression yields nothing or fails are dropped
+func logJQFilter(expr string, dropOther bool) (logFilter, error) {
+	q, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --jq expression: %s", err)
+	}
+
+	code, err := gojq.Compile(q)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --jq expression: %s", err)
+	}
+
+	return func(line string) (string, bool) {
+		v, ok := logJSON(line)
+		if !ok {
+			return line, !dropOther
+		}
+
+		results := []string{}
+
+		iter := code.Run(v)
+
+		for {
+			r, ok := iter.Next()
+			if !ok {
+				break
+			}
+
+			if _, ok := r.(error); ok {
+				break
+			}
+
+			if s, ok := r.(string); ok {
+				results = append(results, s)
+				continue
+			}
+
+			data, err := json.Marshal(r)
+			if err != nil {
+				break
+			}
+
+			results = append(results, string(data))
+		}
+
+		if len(results) == 0 {
+			return line, false
+		}
+
+		return strings.Join(results, "\n"), true
+	}, nil
+}
+
+// logTraceFields are where request and trace ids are usually found when --trace-field is not given
+var logTraceFields = []string{"trace_id", "traceId", "request_id", "requestId"}
+
+// logTraceFilter only passes lines tagged with id, read from the first of fields present in the
+// line's json, where a field may be a dotted path like meta.trace_id, or from a key=value pair
+// in lines without json
+func logTraceFilter(id string, fields []string) logFilter {
+	pairs := make([]*regexp.Regexp, len(fields))
+
+	for i, f := range fields {
+		pairs[i] = regexp.MustCompile(fmt.Sprintf(`(?:^|[\s,;])%s[=:]\s*"?([^\s",;]+)`, regexp.QuoteMeta(f)))
+	}
+
+	return func(line string) (string, bool) {
+		return line, logTraceID(line, fields, pairs) == id
+	}
+}
+
+// logTraceID is the trace id a line is tagged with, or an empty string if it has none
+func logTraceID(line string, fields []string, pairs []*regexp.Regexp) string {
+	if v, ok := logJSON(line); ok {
+		for _, f := range fields {
+			if id, ok := logJSONField(v, f); ok {
+				return id
+			}
+		}
+
+		return ""
+	}
+
+	for _, p := range pairs {
+		if m := p.FindStringSubmatch(line); m != nil {
+			return m[1]
+		}
+	}
+
+	return ""
+}
+
+// logJSONField looks up a dotted path like meta.trace_id in decoded json, scalars are returned
+// as strings so numeric ids match too
+func logJSONField(v interface{}, path string) (string, bool) {
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+
+		if v, ok = m[key]; !ok {
+			return "", false
+		}
+	}
+
+	switch t := v.(type) {
+	case string:
+		return t, true
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(t), true
+	}
+
+	return "", false
+}
+
+// logJSON decodes the json in a line, either the whole line or everything from the first {
+// so that prefixes like timestamps and process names are skipped
+func logJSON(line string) (interface{}, bool) {
+	var v interface{}
+
+	if t := strings.TrimSpace(line); strings.HasPrefix(t, "{") || strings.HasPrefix(t, "[") {
+		if err := json.Unmarshal([]byte(t), &v); err == nil {
+			return v, true
+		}
+	}
+
+	i := strings.IndexByte(line, '{')
+	if i < 0 {
+		return nil, false
+	}
+
+	if err := json.Unmarshal([]byte(line[i:]), &v); err != nil {
+		return nil, false
+	}
+
+	return v, true
+}
+
+// parseSince accepts a duration like 10m or 1h30m, or a bare number of seconds like 30 or 1.5
+func parseSince(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		if f < 0 {
+			return 0, fmt.Errorf("invalid --since %q: must not be negative", s)
+		}
+
+		return time.Duration(f * float64(time.Second)), nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid --since %q: use a duration like 10m or 1h30m, or a number of seconds", s)
+	}
+
+	if d < 0 {
+		return 0, fmt.Errorf("invalid --since %q: must not be negative", s)
+	}
+
+	return d, nil
+}
+
+// releaseTime is when a rack release was created, looked up in the rack release history
+func releaseTime(releases client.Releases, version string) (time.Time, error) {
+	for _, r := range releases {
+		if r.Id == version {
+			return r.Created, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("release %s not found in the rack history, see `convox rack releases`", version)
+}
+
+// isLogTime reports whether s is a time rather than a release version
+func isLogTime(s string) bool {
+	_, err := time.Parse(time.RFC3339, s)
+	return err == nil
+}
+
+// isLogAgo reports whether s is a duration ago like 30m, bare numbers are left for releases
+func isLogAgo(s string) bool {
+	_, err := time.ParseDuration(s)
+	return err == nil
+}
+
+// parseUntil reads --until as a time, a duration ago or a release in the rack history
+func parseUntil(s string, releases client.Releases) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	if d, err := time.ParseDuration(s); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("invalid --until %q: must not be negative", s)
+		}
+
+		return time.Now().Add(-d), nil
+	}
+
+	return releaseTime(releases, s)
+}
+
+// logTime is the timestamp at the start of a rack log line
+func logTime(line string) (time.Time, bool) {
+	i := strings.IndexByte(line, ' ')
+	if i < 0 {
+		return time.Time{}, false
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, line[:i])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// logLine is a rack log line split into the fields available to --format
+type logLine struct {
+	Line      string
+	Message   string
+	Source    string
+	Time      time.Time
+	Timestamp string
+}
+
+// parseLogLine splits line into its timestamp, source and message, a line without a timestamp
+// is all message
+func parseLogLine(line string) logLine {
+	l := logLine{Line: line, Message: line}
+
+	t, ok := logTime(line)
+	if !ok {
+		return l
+	}
+
+	fields := strings.SplitN(line, " ", 3)
+
+	l.Time = t
+	l.Timestamp = fields[0]
+	l.Source = logSource(line)
+	l.Message = strings.TrimPrefix(line, fields[0]+" ")
+
+	if l.Source != "" {
+		l.Message = fields[2]
+	}
+
+	return l
+}
+
+// logFormatFilter writes each line with a text/template. The template is tried against an empty
+// line up front so a bad field name fails before streaming rather than on every line.
+func logFormatFilter(format string) (logFilter, error) {
+	t, err := template.New("format").Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format: %s", err)
+	}
+
+	if err := t.Execute(ioutil.Discard, logLine{}); err != nil {
+		return nil, fmt.Errorf("invalid --format: %s", err)
+	}
+
+	return func(line string) (string, bool) {
+		var buf bytes.Buffer
+
+		if err := t.Execute(&buf, parseLogLine(line)); err != nil {
+			return line, true
+		}
+
+		return buf.String(), true
+	}, nil
+}
+
+// logUntilFilter drops lines written after until, lines without a timestamp are passed
+func logUntilFilter(until time.Time) logFilter {
+	return func(line string) (string, bool) {
+		if t, ok := logTime(line); ok && t.After(until) {
+			return line, false
+		}
+
+		return line, true
+	}
+}
+
+// parseLogSample accepts a sampling rate like 1/100, or a bare 100, and returns the 100
+func parseLogSample(s string) (int, error) {
+	rate := strings.TrimSpace(s)
+
+	if strings.HasPrefix(rate, "1/") {
+		rate = rate[2:]
+	}
+
+	n, err := strconv.Atoi(rate)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid --sample %q: use a rate like 1/100", s)
+	}
+
+	return n, nil
+}
+
+// logSampleFilter passes the first line and then every nth line after it, counting only the
+// lines that reach it so earlier filters are applied before sampling
+func logSampleFilter(n int) logFilter {
+	seen := 0
+
+	return func(line string) (string, bool) {
+		keep := seen%n == 0
+		seen++
+
+		return line, keep
+	}
+}
+
+// logTemplatePatterns replace the variable parts of a line so similar lines share a template
+var logTemplatePatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`), "<time>"},
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>"},
+	{regexp.MustCompile(`\b\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}(:\d+)?\b`), "<ip>"},
+	{regexp.MustCompile(`(?i)\b(0x)?[0-9a-f]*[0-9][0-9a-f]*[a-f][0-9a-f]*\b|\b(0x)?[0-9a-f]*[a-f][0-9a-f]*[0-9][0-9a-f]*\b`), "<hex>"},
+	{regexp.MustCompile(`\d+(\.\d+)?`), "<n>"},
+}
+
+// logTemplate normalizes a line into a template by replacing timestamps, ids and numbers
+func logTemplate(line string) string {
+	for _, p := range logTemplatePatterns {
+		line = p.pattern.ReplaceAllString(line, p.replacement)
+	}
+
+	return strings.Join(strings.Fields(line), " ")
+}
+
+type logGroup struct {
+	Count    int    `json:"count"`
+	Example  string `json:"example"`
+	Template string `json:"template"`
+}
+
+// logAggregator groups lines by template, use add as the last filter of a logWriter
+type logAggregator struct {
+	groups map[string]*logGroup
+}
+
+func newLogAggregator() *logAggregator {
+	return &logAggregator{groups: map[string]*logGroup{}}
+}
+
+func (a *logAggregator) add(line string) (string, bool) {
+	t := logTemplate(line)
+
+	if g, ok := a.groups[t]; ok {
+		g.Count++
+	} else {
+		a.groups[t] = &logGroup{Count: 1, Example: line, Template: t}
+	}
+
+	return line, false
+}
+
+// top returns the n most frequent groups, all of them when n is 0
+func (a *logAggregator) top(n int) []logGroup {
+	groups := []logGroup{}
+
+	for _, g := range a.groups {
+		groups = append(groups, *g)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Template < groups[j].Template
+	})
+
+	if n > 0 && len(groups) > n {
+		groups = groups[:n]
+	}
+
+	return groups
+}
+
+// logSource is the component that wrote a rack log line, like service/web or system, taken from
+// the token after the timestamp and returning an empty string if there is none
+func logSource(line string) string {
+	fields := strings.SplitN(line, " ", 3)
+	if len(fields) < 3 {
+		return ""
+	}
+
+	token := fields[1]
+
+	if strings.HasPrefix(token, "system/") {
+		return "system"
+	}
+
+	if i := strings.Index(token, ":"); i > 0 {
+		return token[:i]
+	}
+
+	return ""
+}
+
+type logSourceCount struct {
+	Source  string  `json:"source"`
+	Lines   int     `json:"lines"`
+	Percent float64 `json:"percent"`
+}
+
+// logSourceCounter tallies lines per source, use add as the last filter of a logWriter
+type logSourceCounter struct {
+	counts map[string]int
+	total  int
+}
+
+func newLogSourceCounter() *logSourceCounter {
+	return &logSourceCounter{counts: map[string]int{}}
+}
+
+func (s *logSourceCounter) add(line string) (string, bool) {
+	source := logSource(line)
+	if source == "" {
+		source = "unknown"
+	}
+
+	s.counts[source]++
+	s.total++
+
+	return line, false
+}
+
+// top returns the n sources with the most lines, all of them when n is 0
+func (s *logSourceCounter) top(n int) []logSourceCount {
+	counts := []logSourceCount{}
+
+	for source, lines := range s.counts {
+		counts = append(counts, logSourceCount{
+			Source:  source,
+			Lines:   lines,
+			Percent: math.Round(float64(lines)/float64(s.total)*1000) / 10,
+		})
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Lines != counts[j].Lines {
+			return counts[i].Lines > counts[j].Lines
+		}
+		return counts[i].Source < counts[j].Source
+	})
+
+	if n > 0 && len(counts) > n {
+		counts = counts[:n]
+	}
+
+	return counts
+}
+
+// logSplitter writes each line to a file per source in a directory, opening files as new sources
+// appear. Use add as a filter of a logWriter, lines it writes still pass through
+type logSplitter struct {
+	dir   string
+	err   error
+	files map[string]*os.File
+}
+
+func newLogSplitter(dir string) (*logSplitter, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &logSplitter{dir: dir, files: map[string]*os.File{}}, nil
+}
+
+func (s *logSplitter) add(line string) (string, bool) {
+	source := logSource(line)
+	if source == "" {
+		source = "unknown"
+	}
+
+	f, ok := s.files[source]
+
+	if !ok {
+		name := filepath.Join(s.dir, strings.Replace(source, "/", "-", -1)+".log")
+
+		var err error
+
+		f, err = os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			if s.err == nil {
+				s.err = err
+			}
+			return line, true
+		}
+
+		s.files[source] = f
+	}
+
+	if _, err := fmt.Fprintln(f, line); err != nil && s.err == nil {
+		s.err = err
+	}
+
+	return line, true
+}
+
+// Close closes every file, returning the first error hit while writing or closing
+func (s *logSplitter) Close() error {
+	for _, f := range s.files {
+		if err := f.Close(); err != nil && s.err == nil {
+			s.err = err
+		}
+	}
+
+	s.files = map[string]*os.File{}
+
+	return s.err
+}
+
+// openLogFile opens the file given with --output-file, replacing its contents unless appending
+func openLogFile(name string, append bool) (*os.File, error) {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+
+	if append {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+
+	return os.OpenFile(name, flags, 0644)
+}
+
+// closeLogOnInterrupt writes out the trailing partial line of w and closes f when the user stops
+// the stream, until the returned func is called
+func closeLogOnInterrupt(w *logWriter, f *os.File) func() {
+	sig := make(chan os.Signal, 1)
+	done := make(chan bool)
+
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case <-sig:
+			w.Close()
+			f.Close()
+			os.Exit(0)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(sig)
+		close(done)
+	}
+}
+
+type logWindow struct {
+	Start  time.Time `json:"start"`
+	Lines  int       `json:"lines"`
+	Errors int       `json:"errors"`
+}
+
+// logWindowCounter tallies lines and error lines per time window, use add as the last filter of
+// a logWriter. Lines without a timestamp are not counted
+type logWindowCounter struct {
+	counts map[time.Time]*logWindow
+	window time.Duration
+}
+
+func newLogWindowCounter(window time.Duration) *logWindowCounter {
+	return &logWindowCounter{counts: map[time.Time]*logWindow{}, window: window}
+}
+
+func (wc *logWindowCounter) add(line string) (string, bool) {
+	t, ok := logTime(line)
+	if !ok {
+		return line, false
+	}
+
+	start := t.UTC().Truncate(wc.window)
+
+	w, ok := wc.counts[start]
+	if !ok {
+		w = &logWindow{Start: start}
+		wc.counts[start] = w
+	}
+
+	w.Lines++
+
+	if l := logLevel(line); l == "error" || l == "fatal" {
+		w.Errors++
+	}
+
+	return line, false
+}
+
+// windows returns every window from the first line to the last in order, including empty ones
+// so gaps in the logs are visible
+func (wc *logWindowCounter) windows() []logWindow {
+	windows := []logWindow{}
+
+	if len(wc.counts) == 0 {
+		return windows
+	}
+
+	var first, last time.Time
+
+	for start := range wc.counts {
+		if first.IsZero() || start.Before(first) {
+			first = start
+		}
+		if start.After(last) {
+			last = start
+		}
+	}
+
+	for start := first; !start.After(last); start = start.Add(wc.window) {
+		if w, ok := wc.counts[start]; ok {
+			windows = append(windows, *w)
+		} else {
+			windows = append(windows, logWindow{Start: start})
+		}
+	}
+
+	return windows
+}
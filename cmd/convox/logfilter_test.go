@@ -0,0 +1,606 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogLevel(t *testing.T) {
+	tests := map[string]string{
+		"2017-01-01T00:00:00Z web/1 level=error msg=\"boom\"":       "error",
+		"2017-01-01T00:00:00Z web/1 {\"level\":\"warning\"}":        "warn",
+		"2017-01-01T00:00:00Z web/1 [panic] runtime error":          "fatal",
+		"2017-01-01T00:00:00Z web/1 INFO starting server":           "info",
+		"2017-01-01T00:00:00Z web/1 GET /errors 200":                "",
+		"2017-01-01T00:00:00Z web/1 information about the response": "",
+	}
+
+	for line, level := range tests {
+		assert.Equal(t, level, logLevel(line), line)
+	}
+}
+
+func TestParseLogLevels(t *testing.T) {
+	levels, err := parseLogLevels("warning, ERROR")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"warn", "error"}, levels)
+
+	_, err = parseLogLevels("error,loud")
+	assert.EqualError(t, err, "unknown log level: loud (expected one of: debug, info, warn, error, fatal)")
+}
+
+func TestLogWriterLevelFilter(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := newLogWriter(&buf, logLevelFilter([]string{"error", "fatal"}))
+
+	w.Write([]byte("web/1 INFO ok\nweb/1 ERR"))
+	w.Write([]byte("OR failed\nweb/1 no level\nweb/1 FATAL"))
+	w.Close()
+
+	assert.Equal(t, "web/1 ERROR failed\nweb/1 FATAL\n", buf.String())
+}
+
+func TestLogWriterMarkerFilter(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := newLogWriter(&buf, logMarkerFilter(regexp.MustCompile("deploy started"), regexp.MustCompile("deploy finished")))
+
+	w.Write([]byte("one\ndeploy started\ntwo\nthree\ndeploy finished\nfour\ndeploy started\nfive\n"))
+	w.Close()
+
+	assert.Equal(t, "two\nthree\n", buf.String())
+}
+
+func TestLogWriterJQFilter(t *testing.T) {
+	f, err := logJQFilter(".path", false)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var buf bytes.Buffer
+
+	w := newLogWriter(&buf, f)
+
+	w.Write([]byte(`{"path":"/one","status":200}` + "\n"))
+	w.Write([]byte(`2017-01-01T00:00:00Z web:RAPP/1234 {"path":"/two"}` + "\n"))
+	w.Write([]byte("plain text\n"))
+	w.Write([]byte(`{"status":500}` + "\n"))
+	w.Write([]byte(`{"path":{"id":3}}` + "\n"))
+	w.Close()
+
+	assert.Equal(t, "/one\n/two\nplain text\nnull\n{\"id\":3}\n", buf.String())
+
+	f, err = logJQFilter(`select(.status >= 500) | .status`, true)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	buf.Reset()
+
+	w = newLogWriter(&buf, f)
+
+	w.Write([]byte("plain text\n" + `{"status":200}` + "\n" + `{"status":503}` + "\n" + `{"status":"x"` + "\n"))
+	w.Close()
+
+	assert.Equal(t, "503\n", buf.String())
+
+	_, err = logJQFilter(".path |", false)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid --jq expression:")
+	}
+}
+
+func TestLogWriterTraceFilter(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := newLogWriter(&buf, logTraceFilter("abc", logTraceFields))
+
+	w.Write([]byte(`2017-01-01T00:00:00Z service/web:1 {"trace_id":"abc","msg":"start"}` + "\n"))
+	w.Write([]byte(`2017-01-01T00:00:01Z service/web:1 {"trace_id":"abcd","msg":"other"}` + "\n"))
+	w.Write([]byte("2017-01-01T00:00:02Z service/worker:2 level=info request_id=abc msg=queued\n"))
+	w.Write([]byte("2017-01-01T00:00:03Z service/worker:2 no id here\n"))
+	w.Write([]byte(`2017-01-01T00:00:04Z service/api:3 {"requestId":"abc"}` + "\n"))
+	w.Close()
+
+	assert.Equal(t, `2017-01-01T00:00:00Z service/web:1 {"trace_id":"abc","msg":"start"}`+"\n"+
+		"2017-01-01T00:00:02Z service/worker:2 level=info request_id=abc msg=queued\n"+
+		`2017-01-01T00:00:04Z service/api:3 {"requestId":"abc"}`+"\n", buf.String())
+
+	buf.Reset()
+
+	w = newLogWriter(&buf, logTraceFilter("42", []string{"meta.trace"}))
+
+	w.Write([]byte(`{"meta":{"trace":42}}` + "\n" + `{"trace":42}` + "\n" + `meta.trace="42" plain` + "\n"))
+	w.Close()
+
+	assert.Equal(t, `{"meta":{"trace":42}}`+"\n"+`meta.trace="42" plain`+"\n", buf.String())
+}
+
+func TestLogWriterSampleFilter(t *testing.T) {
+	for _, s := range []string{"1/3", "3", " 1/3 "} {
+		n, err := parseLogSample(s)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+	}
+
+	for _, s := range []string{"", "1/0", "2/3", "-1", "x"} {
+		_, err := parseLogSample(s)
+		assert.Error(t, err, s)
+	}
+
+	var buf bytes.Buffer
+
+	w := newLogWriter(&buf, logLevelFilter([]string{"error"}), logSampleFilter(2))
+
+	w.Write([]byte("ERROR 1\nINFO a\nERROR 2\nERROR 3\nINFO b\nERROR 4\nERROR 5\n"))
+	w.Close()
+
+	assert.Equal(t, "ERROR 1\nERROR 3\nERROR 5\n", buf.String())
+}
+
+func TestLogSinceRelease(t *testing.T) {
+	created := time.Date(2017, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	releases := client.Releases{
+		{Id: "20170101000000", Created: created},
+	}
+
+	start, err := releaseTime(releases, "20170101000000")
+	assert.NoError(t, err)
+	assert.Equal(t, created, start)
+
+	_, err = releaseTime(releases, "20170202000000")
+	assert.EqualError(t, err, "release 20170202000000 not found in the rack history, see `convox rack releases`")
+
+	var buf bytes.Buffer
+
+	w := newLogWriter(&buf, logUntilFilter(created))
+
+	w.Write([]byte("2017-01-02T15:04:04Z service/web:1 before\n2017-01-02T15:04:05.5Z service/web:1 after\ncontinued\n"))
+	w.Close()
+
+	assert.Equal(t, "2017-01-02T15:04:04Z service/web:1 before\ncontinued\n", buf.String())
+
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/system/releases", Code: 200, Response: releases},
+	)
+
+	defer ts.Close()
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox rack logs --since-release 20170202000000",
+			Exit:    1,
+			Stderr:  "ERROR: release 20170202000000 not found in the rack history, see `convox rack releases`\n",
+		},
+		test.ExecRun{
+			Command: "convox rack logs --since-release 20170101000000 --since 5m",
+			Exit:    1,
+			Stderr:  "ERROR: --since-release can not be combined with --since\n",
+		},
+		test.ExecRun{
+			Command: "convox rack logs --since-release 20170101000000 --until 2016-01-01T00:00:00Z",
+			Exit:    1,
+			Stderr:  "ERROR: --until must be after --since-release\n",
+		},
+	)
+}
+
+func TestLogUntil(t *testing.T) {
+	created := time.Date(2017, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	releases := client.Releases{
+		{Id: "20170101000000", Created: created},
+	}
+
+	until, err := parseUntil("2017-01-01T00:00:00Z", releases)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), until)
+
+	until, err = parseUntil("20170101000000", releases)
+	assert.NoError(t, err)
+	assert.Equal(t, created, until)
+
+	until, err = parseUntil("30m", releases)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(-30*time.Minute), until, time.Second)
+
+	_, err = parseUntil("-30m", releases)
+	assert.EqualError(t, err, `invalid --until "-30m": must not be negative`)
+
+	assert.True(t, isLogAgo("30m"))
+	assert.False(t, isLogAgo("20170101000000"))
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox rack logs --until 30m",
+			Exit:    1,
+			Stderr:  "ERROR: --until must be after --since\n",
+		},
+		test.ExecRun{
+			Command: "convox rack logs --since 1h --until 30m --follow",
+			Exit:    1,
+			Stderr:  "ERROR: --until can not be combined with --follow\n",
+		},
+	)
+}
+
+func TestLogStripANSI(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := newLogWriter(&buf, logStripANSI)
+
+	w.Write([]byte("\x1b[31merror\x1b[0m: failed\n"))
+	w.Write([]byte("\x1b[1;38;5;208mwarn\x1b[m \x1b[2Kcleared\n"))
+	w.Write([]byte("\x1b]0;title\x07\x1b]8;;http://example.org\x1b\\link\x1b]8;;\x1b\\\n"))
+	w.Write([]byte("\x1b7saved\x1b8 plain [brackets]\n"))
+	w.Close()
+
+	assert.Equal(t, "error: failed\nwarn cleared\nlink\nsaved plain [brackets]\n", buf.String())
+}
+
+func TestLogMatchHook(t *testing.T) {
+	matched := make(chan string, 10)
+
+	h := newLogMatchHook(regexp.MustCompile("OutOfMemory"), "", time.Hour)
+	h.run = func(line string) { matched <- line }
+
+	var buf bytes.Buffer
+
+	w := newLogWriter(&buf, h.filter)
+
+	w.Write([]byte("one\nOutOfMemory: web\ntwo\nOutOfMemory: worker\n"))
+	w.Close()
+
+	assert.Equal(t, "one\nOutOfMemory: web\ntwo\nOutOfMemory: worker\n", buf.String())
+
+	select {
+	case line := <-matched:
+		assert.Equal(t, "OutOfMemory: web", line)
+	case <-time.After(time.Second):
+		t.Fatal("hook did not run")
+	}
+
+	select {
+	case line := <-matched:
+		t.Fatalf("hook ran during cooldown: %s", line)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	for in, out := range map[string]time.Duration{
+		"30":     30 * time.Second,
+		"1.5":    1500 * time.Millisecond,
+		" 45 ":   45 * time.Second,
+		"0":      0,
+		"10m":    10 * time.Minute,
+		"1h2m3s": time.Hour + 2*time.Minute + 3*time.Second,
+		"500ms":  500 * time.Millisecond,
+	} {
+		d, err := parseSince(in)
+		if assert.NoError(t, err, in) {
+			assert.Equal(t, out, d, in)
+		}
+	}
+
+	for in, msg := range map[string]string{
+		"":      `invalid --since "": use a duration like 10m or 1h30m, or a number of seconds`,
+		"ten":   `invalid --since "ten": use a duration like 10m or 1h30m, or a number of seconds`,
+		"10x":   `invalid --since "10x": use a duration like 10m or 1h30m, or a number of seconds`,
+		"-5":    `invalid --since "-5": must not be negative`,
+		"-1h":   `invalid --since "-1h": must not be negative`,
+		"1e400": `invalid --since "1e400": use a duration like 10m or 1h30m, or a number of seconds`,
+		"NaN":   `invalid --since "NaN": use a duration like 10m or 1h30m, or a number of seconds`,
+	} {
+		_, err := parseSince(in)
+		assert.EqualError(t, err, msg, in)
+	}
+}
+
+func TestLogTemplate(t *testing.T) {
+	assert.Equal(t, "<time> web/<hex> error: request <uuid> from <ip> failed after <n>ms",
+		logTemplate("2017-05-02T10:00:01.123Z web/4f9a2c1b error: request 3b241101-e2bb-4255-8caf-4136c566a962 from 10.0.1.15:443 failed after 350ms"))
+	assert.Equal(t, "error: user <n> not found", logTemplate("error:   user 42 not found"))
+	assert.Equal(t, "error: cache miss", logTemplate("error: cache miss"))
+}
+
+func TestLogAggregator(t *testing.T) {
+	var buf bytes.Buffer
+
+	a := newLogAggregator()
+
+	w := newLogWriter(&buf, a.add)
+
+	w.Write([]byte("error: user 1 not found\nerror: timeout after 30s\nerror: user 2 not found\nerror: user 3 not found\nerror: timeout after 10s\nfatal: disk full\n"))
+	w.Close()
+
+	assert.Equal(t, "", buf.String())
+
+	assert.Equal(t, []logGroup{
+		{Count: 3, Example: "error: user 1 not found", Template: "error: user <n> not found"},
+		{Count: 2, Example: "error: timeout after 30s", Template: "error: timeout after <n>s"},
+	}, a.top(2))
+
+	assert.Len(t, a.top(0), 3)
+}
+
+func TestLogWriterHeartbeat(t *testing.T) {
+	var buf, beats bytes.Buffer
+	var lock sync.Mutex
+
+	w := newLogWriter(&buf)
+
+	stop := w.heartbeat(lockedWriter{&beats, &lock}, 40*time.Millisecond, false)
+
+	time.Sleep(100 * time.Millisecond)
+	stop()
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	assert.Contains(t, beats.String(), "still connected, no new logs\n")
+	assert.Equal(t, "", buf.String())
+}
+
+type lockedWriter struct {
+	w    io.Writer
+	lock *sync.Mutex
+}
+
+func (lw lockedWriter) Write(data []byte) (int, error) {
+	lw.lock.Lock()
+	defer lw.lock.Unlock()
+
+	return lw.w.Write(data)
+}
+
+func TestLogSourceCounter(t *testing.T) {
+	assert.Equal(t, "service/web", logSource("2017-01-01T00:00:00Z service/web:RAPP/1234 hello"))
+	assert.Equal(t, "service/web", logSource("2017-01-01T00:00:00Z service/web:1234 hello"))
+	assert.Equal(t, "system", logSource("2017-01-01T00:00:00Z system/i-1234 hello"))
+	assert.Equal(t, "", logSource("2017-01-01T00:00:00Z hello world"))
+	assert.Equal(t, "", logSource("hello"))
+
+	s := newLogSourceCounter()
+
+	var buf bytes.Buffer
+
+	w := newLogWriter(&buf, s.add)
+
+	w.Write([]byte("2017-01-01T00:00:00Z service/web:RAPP/1 one\n"))
+	w.Write([]byte("2017-01-01T00:00:00Z service/web:RAPP/2 two\n"))
+	w.Write([]byte("2017-01-01T00:00:00Z service/worker:RAPP/3 three\n"))
+	w.Write([]byte("2017-01-01T00:00:00Z plain line\n"))
+	w.Close()
+
+	assert.Equal(t, "", buf.String())
+
+	assert.Equal(t, []logSourceCount{
+		{Source: "service/web", Lines: 2, Percent: 50},
+		{Source: "service/worker", Lines: 1, Percent: 25},
+		{Source: "unknown", Lines: 1, Percent: 25},
+	}, s.top(0))
+
+	assert.Len(t, s.top(1), 1)
+}
+
+func TestLogWindowCounter(t *testing.T) {
+	wc := newLogWindowCounter(time.Minute)
+
+	var buf bytes.Buffer
+
+	w := newLogWriter(&buf, wc.add)
+
+	w.Write([]byte("2017-01-01T00:00:05Z service/web:1 level=info one\n"))
+	w.Write([]byte("2017-01-01T00:00:59Z service/web:1 level=error two\n"))
+	w.Write([]byte("2017-01-01T00:03:00Z service/web:1 FATAL three\n"))
+	w.Write([]byte("no timestamp\n"))
+	w.Close()
+
+	assert.Equal(t, "", buf.String())
+
+	at := func(m int) time.Time { return time.Date(2017, 1, 1, 0, m, 0, 0, time.UTC) }
+
+	windows := wc.windows()
+
+	assert.Equal(t, []logWindow{
+		{Start: at(0), Lines: 2, Errors: 1},
+		{Start: at(1)},
+		{Start: at(2)},
+		{Start: at(3), Lines: 1, Errors: 1},
+	}, windows)
+
+	assert.Equal(t, strings.Repeat("!", 20)+strings.Repeat("#", 20), logWindowBar(windows[0], 2))
+	assert.Equal(t, "", logWindowBar(windows[1], 2))
+	assert.Equal(t, strings.Repeat("!", 20), logWindowBar(windows[3], 2))
+
+	assert.Equal(t, []logWindow{}, newLogWindowCounter(time.Minute).windows())
+}
+
+func TestFollowLogsReconnect(t *testing.T) {
+	var status bytes.Buffer
+
+	results := []error{
+		fmt.Errorf("connection lost: read tcp: i/o timeout"),
+		fmt.Errorf("dial tcp: connection refused"),
+		nil,
+	}
+
+	sinces := []time.Duration{}
+
+	stream := func(since time.Duration) error {
+		sinces = append(sinces, since)
+		err := results[0]
+		results = results[1:]
+		return err
+	}
+
+	resume := func() time.Duration { return 30 * time.Second }
+
+	assert.NoError(t, followLogs(stream, 2*time.Minute, resume, time.Millisecond, &status))
+	assert.Equal(t, []time.Duration{2 * time.Minute, 30 * time.Second, 30 * time.Second}, sinces)
+	assert.Equal(t, "connection lost: read tcp: i/o timeout, reconnecting in 1ms\ndial tcp: connection refused, reconnecting in 2ms\n", status.String())
+
+	// errors other than a lost connection are returned right away
+	calls := 0
+
+	err := followLogs(func(time.Duration) error {
+		calls++
+		return fmt.Errorf("unauthorized")
+	}, 0, resume, time.Millisecond, &status)
+
+	assert.EqualError(t, err, "unauthorized")
+	assert.Equal(t, 1, calls)
+
+	// reconnecting gives up after a few failures in a row
+	calls = 0
+
+	err = followLogs(func(time.Duration) error {
+		calls++
+
+		if calls == 1 {
+			return fmt.Errorf("connection lost: EOF")
+		}
+
+		return fmt.Errorf("dial tcp: connection refused")
+	}, 0, resume, time.Millisecond, ioutil.Discard)
+
+	assert.EqualError(t, err, "dial tcp: connection refused")
+	assert.Equal(t, 1+logReconnectAttempts, calls)
+}
+
+func TestLogSplitter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "split")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	s, err := newLogSplitter(filepath.Join(dir, "logs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	skip := func(line string) (string, bool) { return line, !strings.Contains(line, "skip") }
+
+	w := newLogWriter(&buf, skip, s.add)
+
+	w.Write([]byte("2017-01-01T00:00:00Z service/web:R1/abc ok\n"))
+	w.Write([]byte("2017-01-01T00:00:01Z system/aws/ecs started\n"))
+	w.Write([]byte("2017-01-01T00:00:02Z service/web:R1/abc skip this\n"))
+	w.Write([]byte("2017-01-01T00:00:03Z service/web:R1/def done\n"))
+	w.Write([]byte("no source\n"))
+
+	assert.NoError(t, s.Close())
+
+	read := func(name string) string {
+		data, _ := ioutil.ReadFile(filepath.Join(dir, "logs", name))
+		return string(data)
+	}
+
+	assert.Equal(t, "2017-01-01T00:00:00Z service/web:R1/abc ok\n2017-01-01T00:00:03Z service/web:R1/def done\n", read("service-web.log"))
+	assert.Equal(t, "2017-01-01T00:00:01Z system/aws/ecs started\n", read("system.log"))
+	assert.Equal(t, "no source\n", read("unknown.log"))
+	assert.Equal(t, 4, strings.Count(buf.String(), "\n"))
+}
+
+func TestLogOutputFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "rack.log")
+
+	write := func(append bool, data string) {
+		f, err := openLogFile(file, append)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w := newLogWriter(f)
+		w.Write([]byte(data))
+
+		assert.NoError(t, w.Close())
+		assert.NoError(t, f.Close())
+	}
+
+	read := func() string {
+		data, _ := ioutil.ReadFile(file)
+		return string(data)
+	}
+
+	write(false, "one\ntwo")
+	write(true, "three\n")
+
+	assert.Equal(t, "one\ntwo\nthree\n", read())
+
+	write(false, "four\n")
+
+	assert.Equal(t, "four\n", read())
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox rack logs --append",
+			Exit:    1,
+			Stderr:  "ERROR: --append requires --output-file\n",
+		},
+		test.ExecRun{
+			Command: "convox rack logs --output-file " + file + " --count-by-source",
+			Exit:    1,
+			Stderr:  "ERROR: --output-file can not be combined with --aggregate, --count-by-source or --group-by-window\n",
+		},
+	)
+}
+
+func TestLogFormatFilter(t *testing.T) {
+	l := parseLogLine("2017-01-01T00:00:00Z service/web:R1/abc GET /health 200")
+
+	assert.Equal(t, "2017-01-01T00:00:00Z", l.Timestamp)
+	assert.Equal(t, "service/web", l.Source)
+	assert.Equal(t, "GET /health 200", l.Message)
+	assert.Equal(t, 2017, l.Time.Year())
+
+	assert.Equal(t, logLine{Line: "no timestamp here", Message: "no timestamp here"}, parseLogLine("no timestamp here"))
+	assert.Equal(t, "just a message", parseLogLine("2017-01-01T00:00:00Z just a message").Message)
+
+	f, err := logFormatFilter(`{{.Source}} {{.Time.Unix}} {{.Message}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	w := newLogWriter(&buf, f)
+
+	w.Write([]byte("2017-01-01T00:00:00Z service/web:R1/abc GET /health 200\n"))
+	w.Write([]byte("2017-01-01T00:00:01Z system/aws/ecs started\n"))
+
+	assert.Equal(t, "service/web 1483228800 GET /health 200\nsystem 1483228801 started\n", buf.String())
+
+	_, err = logFormatFilter(`{{.Message`)
+	assert.EqualError(t, err, "invalid --format: template: format:1: unclosed action")
+
+	_, err = logFormatFilter(`{{.Level}}`)
+	assert.Contains(t, err.Error(), "invalid --format: template: format:1:2: executing \"format\" at <.Level>: can't evaluate field Level")
+}
@@ -31,7 +31,7 @@ func TestInvalidLogin(t *testing.T) {
 		test.ExecRun{
 			Command: "convox login --password foobar BAD",
 			Env:     map[string]string{"CONVOX_CONFIG": temp},
-			Exit:    1,
+			Exit:    4,
 			Stderr:  "ERROR",
 		},
 	)
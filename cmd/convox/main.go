@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/mail"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -73,11 +74,29 @@ func init() {
 
 func main() {
 	app := stdcli.New()
-	app.Flags = []cli.Flag{appFlag, rackFlag}
+	app.Flags = []cli.Flag{appFlag, configFlag, rackFlag, urlFlag}
 	app.Version = Version
 
+	before := app.Before
+
+	// --config has to be in place before any command runs. An existing directory replaces the
+	// whole config like CONVOX_CONFIG, anything else is the file the configured racks are kept in
+	app.Before = func(c *cli.Context) error {
+		if path := c.GlobalString("config"); path != "" {
+			if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+				ConfigRoot = path
+			} else {
+				rackConfigFile = path
+			}
+		}
+
+		return before(c)
+	}
+
 	terminalSetup()
 
+	stdcli.DefaultWriter.JSONErrors = stdcli.OutputJSON(os.Args[1:])
+
 	err := app.Run(os.Args)
 
 	if err != nil {
@@ -114,10 +133,12 @@ func main() {
 			}
 		}
 
-		if _, ok := err.(stdcli.ErrorStdCli); !ok {
+		switch err.(type) {
+		case stdcli.ErrorStdCli, stdcli.ErrorCode:
+		default:
 			stdcli.Error(err)
 		}
-		os.Exit(1)
+		os.Exit(stdcli.ExitCode(err))
 	}
 }
 
@@ -130,6 +151,15 @@ type Rack struct {
 type Racks []Rack
 
 func currentCredentials(c *cli.Context) (string, string, string, error) {
+	if u := helpers.Coalesce(c.GlobalString("url"), os.Getenv("CONVOX_RACK_URL")); u != "" {
+		host, password, err := parseRackURL(u)
+		if err != nil {
+			return "", "", "", err
+		}
+
+		return "", host, password, nil
+	}
+
 	if os.Getenv("CONVOX_HOST") != "" {
 		return "", os.Getenv("CONVOX_HOST"), os.Getenv("CONVOX_PASSWORD"), nil
 	}
@@ -159,7 +189,7 @@ func currentCredentials(c *cli.Context) (string, string, string, error) {
 		racks := rackList()
 
 		if len(racks) < 1 {
-			return "", "", "", fmt.Errorf("please login with `convox login`")
+			return "", "", "", stdcli.ExitWith(stdcli.ExitAuth, fmt.Errorf("please login with `convox login`"))
 		}
 
 		if len(racks) > 1 {
@@ -189,6 +219,31 @@ func currentCredentials(c *cli.Context) (string, string, string, error) {
 	return name, rack.Host, password, nil
 }
 
+// parseRackURL extracts the host and password from a rack url such as the
+// RACK_URL printed by `convox rack install`
+func parseRackURL(s string) (string, string, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid rack url: %s", err)
+	}
+
+	if u.Host == "" {
+		return "", "", fmt.Errorf("invalid rack url: no host")
+	}
+
+	password := ""
+
+	if u.User != nil {
+		password, _ = u.User.Password()
+	}
+
+	if password == "" {
+		return "", "", fmt.Errorf("invalid rack url: no password")
+	}
+
+	return u.Host, password, nil
+}
+
 func currentRack(c *cli.Context) string {
 	cr := readConfig("rack")
 	rackFlag := stdcli.RecoverFlag(c, "rack")
@@ -200,9 +255,11 @@ func rack(c *cli.Context) *sdk.Client {
 	name, host, password, err := currentCredentials(c)
 	if err != nil {
 		stdcli.Error(err)
-		os.Exit(1)
+		os.Exit(stdcli.ExitCode(err))
 	}
 
+	rememberRack(c, name, host)
+
 	cl, err := sdk.New(fmt.Sprintf("https://%s@%s", password, host))
 	if err != nil {
 		stdcli.Error(err)
@@ -218,9 +275,11 @@ func rackClient(c *cli.Context) *client.Client {
 	name, host, password, err := currentCredentials(c)
 	if err != nil {
 		stdcli.Error(err)
-		os.Exit(1)
+		os.Exit(stdcli.ExitCode(err))
 	}
 
+	rememberRack(c, name, host)
+
 	cl := client.New(host, password, Version)
 
 	cl.Rack = name
@@ -228,6 +287,35 @@ func rackClient(c *cli.Context) *client.Client {
 	return cl
 }
 
+// rememberRack makes a rack chosen with --rack the default for later commands, the same as
+// switching to it
+func rememberRack(c *cli.Context, name, host string) {
+	if name == "" || stdcli.RecoverFlag(c, "rack") == "" || strings.TrimSpace(readConfig("rack")) == name {
+		return
+	}
+
+	switchRack(Rack{Host: host, Name: name})
+}
+
+// namedRackClient returns a client for a rack other than the current one
+func namedRackClient(name string) (*client.Client, error) {
+	r, err := matchRack(name)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := getLogin(r.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	cl := client.New(r.Host, password, Version)
+
+	cl.Rack = r.Name
+
+	return cl, nil
+}
+
 func rackGet(name string) (*Rack, error) {
 	racks := rackList()
 
@@ -242,15 +330,31 @@ func rackGet(name string) (*Rack, error) {
 
 func rackList() Racks {
 	racks := Racks{}
+	seen := map[string]bool{}
+
+	add := func(rs Racks) {
+		for _, r := range rs {
+			if !seen[r.Name] {
+				racks = append(racks, r)
+				seen[r.Name] = true
+			}
+		}
+	}
+
+	// configured racks come first so they win over a remote rack with the same name
+	crs, err := configuredRacks()
+	if err == nil {
+		add(crs)
+	}
 
 	rrs, err := remoteRacks()
 	if err == nil {
-		racks = append(racks, rrs...)
+		add(rrs)
 	}
 
 	lrs, err := localRacks()
 	if err == nil {
-		racks = append(racks, lrs...)
+		add(lrs)
 	}
 
 	sort.Slice(racks, func(i, j int) bool {
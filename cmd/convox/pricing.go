@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+)
+
+// hoursPerMonth is the average number of hours in a month used by aws for monthly pricing
+const hoursPerMonth = 730
+
+// awsPricingRegion is the region the embedded aws prices are for
+const awsPricingRegion = "us-east-1"
+
+// awsInstancePrices are on-demand linux prices in USD per hour
+var awsInstancePrices = map[string]float64{
+	"c3.large":    0.105,
+	"c3.xlarge":   0.21,
+	"c3.2xlarge":  0.42,
+	"c4.large":    0.10,
+	"c4.xlarge":   0.199,
+	"c4.2xlarge":  0.398,
+	"c4.4xlarge":  0.796,
+	"c4.8xlarge":  1.591,
+	"c5.large":    0.085,
+	"c5.xlarge":   0.17,
+	"c5.2xlarge":  0.34,
+	"c5.4xlarge":  0.68,
+	"c5.9xlarge":  1.53,
+	"m3.medium":   0.067,
+	"m3.large":    0.133,
+	"m3.xlarge":   0.266,
+	"m3.2xlarge":  0.532,
+	"m4.large":    0.10,
+	"m4.xlarge":   0.20,
+	"m4.2xlarge":  0.40,
+	"m4.4xlarge":  0.80,
+	"m4.10xlarge": 2.00,
+	"m5.large":    0.096,
+	"m5.xlarge":   0.192,
+	"m5.2xlarge":  0.384,
+	"m5.4xlarge":  0.768,
+	"r4.large":    0.133,
+	"r4.xlarge":   0.266,
+	"r4.2xlarge":  0.532,
+	"r4.4xlarge":  1.064,
+	"r5.large":    0.126,
+	"r5.xlarge":   0.252,
+	"r5.2xlarge":  0.504,
+	"t2.nano":     0.0058,
+	"t2.micro":    0.0116,
+	"t2.small":    0.023,
+	"t2.medium":   0.0464,
+	"t2.large":    0.0928,
+	"t2.xlarge":   0.1856,
+	"t2.2xlarge":  0.3712,
+	"t3.nano":     0.0052,
+	"t3.micro":    0.0104,
+	"t3.small":    0.0208,
+	"t3.medium":   0.0416,
+	"t3.large":    0.0832,
+	"t3.xlarge":   0.1664,
+	"t3.2xlarge":  0.3328,
+}
+
+// instancePrice is the hourly price of one instance of a type, or an error saying why it is unknown
+func instancePrice(provider, region, typ string) (float64, error) {
+	switch provider {
+	case "aws":
+		if region != awsPricingRegion {
+			return 0, fmt.Errorf("no pricing data for region %s", region)
+		}
+
+		price, ok := awsInstancePrices[typ]
+		if !ok {
+			return 0, fmt.Errorf("no pricing data for instance type %s", typ)
+		}
+
+		return price, nil
+	default:
+		return 0, fmt.Errorf("no pricing data for provider %s", provider)
+	}
+}
@@ -1,22 +1,37 @@
 package main
 
 import (
+	"bytes"
+	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/cheggaaa/pb"
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"github.com/convox/rack/structs"
 )
 
+// ProgressMeter shows a progress bar, or when stdcli.Progress does not allow redrawing it a
+// single line with the amount transferred once the transfer is done
 type ProgressMeter struct {
 	after    string
 	bar      *pb.ProgressBar
+	current  int64
 	finished bool
+	plain    bool
 	prefix   string
 	out      io.Writer
 	total    int64
 }
 
 func (pm *ProgressMeter) Start(total int64) {
+	pm.total = total
+
+	if pm.plain {
+		return
+	}
+
 	pm.bar = pb.New64(total)
 	pm.bar.Prefix(pm.prefix)
 	pm.bar.SetMaxWidth(70)
@@ -24,12 +39,14 @@ func (pm *ProgressMeter) Start(total int64) {
 	pm.bar.SetRefreshRate(200 * time.Millisecond)
 	pm.bar.Output = pm.out
 	pm.bar.Start()
-
-	pm.total = total
 }
 
 func (pm *ProgressMeter) Progress(current int64) {
-	pm.bar.Set64(current)
+	pm.current = current
+
+	if pm.bar != nil {
+		pm.bar.Set64(current)
+	}
 
 	if current >= pm.total {
 		pm.Finish()
@@ -41,7 +58,11 @@ func (pm *ProgressMeter) Finish() {
 		return
 	}
 
-	pm.bar.Finish()
+	if pm.bar != nil {
+		pm.bar.Finish()
+	} else {
+		fmt.Fprintf(pm.out, "%s%s / %s\n", pm.prefix, pb.Format(pm.current).To(pb.U_BYTES), pb.Format(pm.total).To(pb.U_BYTES))
+	}
 
 	if pm.after != "" {
 		pm.out.Write([]byte(pm.after))
@@ -54,6 +75,59 @@ func progress(prefix, after string, out io.Writer) *ProgressMeter {
 	return &ProgressMeter{
 		after:  after,
 		out:    out,
+		plain:  !stdcli.Progress(),
 		prefix: prefix,
 	}
 }
+
+// installEventWriter turns the text progress a provider writes during install into install
+// events, one per line. Lines look like "status: resource" or "Status Name: resource", anything
+// else is sent with only a message
+type installEventWriter struct {
+	buf   bytes.Buffer
+	event func(structs.SystemInstallEvent)
+}
+
+func newInstallEventWriter(event func(structs.SystemInstallEvent)) *installEventWriter {
+	return &installEventWriter{event: event}
+}
+
+func (w *installEventWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+
+		w.line(string(w.buf.Next(i + 1)))
+	}
+
+	return len(p), nil
+}
+
+// Flush sends any trailing output that did not end in a newline
+func (w *installEventWriter) Flush() {
+	if w.buf.Len() > 0 {
+		w.line(w.buf.String())
+		w.buf.Reset()
+	}
+}
+
+func (w *installEventWriter) line(line string) {
+	line = strings.TrimSpace(logANSIPattern.ReplaceAllString(line, ""))
+
+	if line == "" {
+		return
+	}
+
+	e := structs.SystemInstallEvent{Message: line, Timestamp: time.Now()}
+
+	if parts := strings.SplitN(line, ": ", 2); len(parts) == 2 {
+		e.Status = strings.ToLower(strings.Fields(parts[0])[0])
+		e.Resource = strings.TrimSpace(parts[1])
+	}
+
+	w.event(e)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/convox/rack/structs"
+	"github.com/convox/rack/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressMeterPlain(t *testing.T) {
+	var buf bytes.Buffer
+
+	pm := progress("Uploading: ", "Importing build... ", &buf)
+
+	// tests never run with a terminal so there is nothing to redraw
+	assert.True(t, pm.plain)
+
+	pm.Start(2048)
+	pm.Progress(1024)
+
+	assert.Equal(t, "", buf.String())
+
+	pm.Progress(2048)
+	pm.Finish()
+
+	assert.Equal(t, "Uploading: 2.00 KiB / 2.00 KiB\nImporting build... ", buf.String())
+}
+
+func TestInstallEventWriter(t *testing.T) {
+	events := []structs.SystemInstallEvent{}
+
+	w := newInstallEventWriter(func(e structs.SystemInstallEvent) {
+		events = append(events, e)
+	})
+
+	fmt.Fprint(w, "pulling: convox/rack:20170101000000\nCreated VPC: vpc-")
+	fmt.Fprint(w, "123\n\n\x1b[32mWaiting for stack\x1b[0m")
+
+	assert.Len(t, events, 2)
+
+	w.Flush()
+
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, "pulling", events[0].Status)
+		assert.Equal(t, "convox/rack:20170101000000", events[0].Resource)
+		assert.Equal(t, "created", events[1].Status)
+		assert.Equal(t, "vpc-123", events[1].Resource)
+		assert.Equal(t, "Created VPC: vpc-123", events[1].Message)
+		assert.Equal(t, "", events[2].Status)
+		assert.Equal(t, "Waiting for stack", events[2].Message)
+		assert.False(t, events[2].Timestamp.IsZero())
+	}
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox rack install --progress xml local",
+			Exit:    1,
+			Stderr:  "ERROR: unknown progress format: xml\n",
+		},
+	)
+}
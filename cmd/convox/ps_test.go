@@ -87,7 +87,7 @@ var psMissingProcessID = `ERROR: 1 argument is required: <process id>`
 //     // ps stop
 //     test.ExecRun{
 //       Command:  "convox ps stop",
-//       Exit:     129,
+//       Exit:     2,
 //       Stderr:   psMissingProcessID,
 //       OutMatch: psStopUsage,
 //     },
@@ -122,7 +122,7 @@ var psMissingProcessID = `ERROR: 1 argument is required: <process id>`
 //     // ps info
 //     test.ExecRun{
 //       Command:  "convox ps info",
-//       Exit:     129,
+//       Exit:     2,
 //       Stderr:   psMissingProcessID,
 //       OutMatch: psInfoUsage,
 //     },
@@ -187,14 +187,14 @@ func TestPsInfoMissingArg(t *testing.T) {
 		test.ExecRun{
 			Command:  "convox ps info",
 			Env:      DebugfulEnv,
-			Exit:     129,
+			Exit:     2,
 			OutMatch: psInfoUsage,
 			Stderr:   psMissingProcessID,
 		},
 		test.ExecRun{
 			Command:  "convox ps info",
 			Env:      DebuglessEnv,
-			Exit:     129,
+			Exit:     2,
 			OutMatch: psInfoUsage,
 		},
 	)
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/convox/rack/client"
+)
+
+// rackConfigFile is the rack config given with --config, empty for racks.json in the config
+// directory
+var rackConfigFile string
+
+func rackConfigPath() string {
+	if rackConfigFile != "" {
+		return rackConfigFile
+	}
+
+	return filepath.Join(ConfigRoot, "racks.json")
+}
+
+// rackConfig is the list of racks added with `convox rack add`, their passwords are kept with
+// the other logins in the auth config
+type rackConfig struct {
+	Racks []rackConfigEntry `json:"racks"`
+}
+
+type rackConfigEntry struct {
+	Host string `json:"host"`
+	Name string `json:"name"`
+}
+
+func (rc *rackConfig) find(name string) int {
+	for i, r := range rc.Racks {
+		if r.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// readRackConfig reads the configured racks, a missing config has none
+func readRackConfig() (*rackConfig, error) {
+	data, _ := ioutil.ReadFile(rackConfigPath())
+	if len(data) == 0 {
+		return &rackConfig{Racks: []rackConfigEntry{}}, nil
+	}
+
+	var rc rackConfig
+
+	if err := json.Unmarshal(data, &rc); err != nil {
+		return nil, fmt.Errorf("invalid rack config %s: %s", rackConfigPath(), err)
+	}
+
+	return &rc, nil
+}
+
+func writeRackConfig(rc *rackConfig) error {
+	data, err := json.MarshalIndent(rc, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	file := rackConfigPath()
+
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(file, data, 0600)
+}
+
+// configuredRacks lists the configured racks alongside the ones from the console and docker
+func configuredRacks() (Racks, error) {
+	rc, err := readRackConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	racks := Racks{}
+
+	for _, r := range rc.Racks {
+		racks = append(racks, Rack{Host: r.Host, Name: r.Name, Status: "configured"})
+	}
+
+	return racks, nil
+}
+
+// migrateRackConfig creates the rack config the first time it is managed. A login made
+// directly to a rack with `convox login <host>` becomes its first entry, logins to a console
+// are left alone since the console lists their racks. A file given with --config starts empty
+func migrateRackConfig() (*rackConfig, error) {
+	if _, err := os.Stat(rackConfigPath()); err == nil || rackConfigFile != "" {
+		return readRackConfig()
+	}
+
+	rc := &rackConfig{Racks: []rackConfigEntry{}}
+
+	host := strings.TrimSpace(readConfig("host"))
+
+	if password, err := getLogin(host); host != "" && err == nil && password != "" {
+		cl := client.New(host, password, Version)
+
+		if _, err := cl.Racks(); err != nil && strings.Contains(err.Error(), "pointing directly at a Rack") {
+			if s, err := cl.GetSystem(); err == nil {
+				rc.Racks = append(rc.Racks, rackConfigEntry{Host: host, Name: s.Name})
+			}
+		}
+	}
+
+	if err := writeRackConfig(rc); err != nil {
+		return nil, err
+	}
+
+	return rc, nil
+}
+
+// addConfiguredRack saves a named rack and its password, replacing a rack with the same name
+func addConfiguredRack(name, host, password string) error {
+	rc, err := migrateRackConfig()
+	if err != nil {
+		return err
+	}
+
+	entry := rackConfigEntry{Host: host, Name: name}
+
+	if i := rc.find(name); i >= 0 {
+		rc.Racks[i] = entry
+	} else {
+		rc.Racks = append(rc.Racks, entry)
+	}
+
+	if err := addLogin(host, password); err != nil {
+		return err
+	}
+
+	return writeRackConfig(rc)
+}
+
+// removeConfiguredRack forgets a named rack, and its password unless another login still uses it
+func removeConfiguredRack(name string) error {
+	rc, err := migrateRackConfig()
+	if err != nil {
+		return err
+	}
+
+	i := rc.find(name)
+	if i < 0 {
+		return fmt.Errorf("no such configured rack: %s", name)
+	}
+
+	host := rc.Racks[i].Host
+
+	rc.Racks = append(rc.Racks[:i], rc.Racks[i+1:]...)
+
+	if err := writeRackConfig(rc); err != nil {
+		return err
+	}
+
+	for _, r := range rc.Racks {
+		if r.Host == host {
+			return nil
+		}
+	}
+
+	if strings.TrimSpace(readConfig("host")) == host {
+		return nil
+	}
+
+	return removeLogin(host)
+}
@@ -34,19 +34,19 @@ func TestScaleCmd(t *testing.T) {
 		test.ExecRun{
 			Command:  "convox scale foo bar",
 			OutMatch: scaleUsage,
-			Exit:     129,
+			Exit:     2,
 		},
 		test.ExecRun{
 			Command:  "convox scale --foo",
 			OutMatch: "Incorrect Usage: flag provided but not defined: -foo\n\n" + scaleUsage,
 			Stderr:   "ERROR: flag provided but not defined: -foo\n",
-			Exit:     1,
+			Exit:     2,
 		},
 		test.ExecRun{
 			Command:  "convox scale --cpu",
 			OutMatch: "Incorrect Usage: flag needs an argument: -cpu\n\n" + scaleUsage,
 			Stderr:   "ERROR: flag needs an argument: -cpu\n",
-			Exit:     1,
+			Exit:     2,
 		},
 		test.ExecRun{
 			Command: "convox scale --cpu=1",
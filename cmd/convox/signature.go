@@ -0,0 +1,122 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+// signatureSuffix is appended to the url of a release artifact to find its detached signature
+const signatureSuffix = ".sig"
+
+var verifySignatureFlag = cli.BoolFlag{
+	Name:  "verify-signature",
+	Usage: "verify the signature of the rack template before applying it, requires --signature-key",
+}
+
+var signatureKeyFlag = cli.StringFlag{
+	Name:   "signature-key",
+	EnvVar: "CONVOX_SIGNATURE_KEY",
+	Usage:  "pem encoded ed25519 public key used by --verify-signature",
+}
+
+// verifyTemplateSignature checks the signature of the template at url when --verify-signature is
+// set, returning the sha256 digest of the verified template or "" when nothing was verified
+func verifyTemplateSignature(c *cli.Context, url string) (string, error) {
+	if !c.Bool("verify-signature") {
+		return "", nil
+	}
+
+	if c.String("signature-key") == "" {
+		return "", fmt.Errorf("--verify-signature requires --signature-key or CONVOX_SIGNATURE_KEY")
+	}
+
+	key, err := readSignatureKey(c.String("signature-key"))
+	if err != nil {
+		return "", err
+	}
+
+	return verifyTemplate(url, key)
+}
+
+// readSignatureKey reads a pem encoded ed25519 public key from file
+func readSignatureKey(file string) (ed25519.PublicKey, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no pem encoded public key in %s", file)
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse public key in %s: %s", file, err)
+	}
+
+	key, ok := pub.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key in %s is not an ed25519 key", file)
+	}
+
+	return key, nil
+}
+
+// verifyTemplate fetches the template at url and its base64 encoded signature at url.sig, checks
+// the signature against key and returns the sha256 digest of the template so the rack can make
+// sure it applies the same bytes
+func verifyTemplate(url string, key ed25519.PublicKey) (string, error) {
+	template, err := fetchArtifact(url)
+	if err != nil {
+		return "", fmt.Errorf("could not fetch template: %s", err)
+	}
+
+	sig, err := fetchArtifact(url + signatureSuffix)
+	if err != nil {
+		return "", fmt.Errorf("could not fetch template signature: %s", err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return "", fmt.Errorf("template signature is malformed: %s", url+signatureSuffix)
+	}
+
+	if !ed25519.Verify(key, template, signature) {
+		return "", fmt.Errorf("template signature is invalid: %s", url)
+	}
+
+	sum := sha256.Sum256(template)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// artifactClient downloads release artifacts, a stalled download fails instead of hanging
+var artifactClient = &http.Client{Timeout: 60 * time.Second}
+
+// fetchArtifact downloads a published release artifact
+func fetchArtifact(url string) ([]byte, error) {
+	res, err := artifactClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, fmt.Errorf("%s: %s", url, res.Status)
+	}
+
+	return ioutil.ReadAll(res.Body)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyTemplate(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	template := []byte(`{"Parameters":{}}`)
+
+	files := map[string][]byte{
+		"/good/rack.json":     template,
+		"/good/rack.json.sig": []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, template)) + "\n"),
+		"/bad/rack.json":      []byte(`{"Parameters":{"Evil":{}}}`),
+		"/bad/rack.json.sig":  []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, template))),
+		"/junk/rack.json":     template,
+		"/junk/rack.json.sig": []byte("not a signature"),
+		"/unsigned/rack.json": template,
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Write(data)
+	}))
+
+	defer ts.Close()
+
+	digest, err := verifyTemplate(ts.URL+"/good/rack.json", pub)
+	assert.NoError(t, err)
+
+	sum := sha256.Sum256(template)
+	assert.Equal(t, hex.EncodeToString(sum[:]), digest)
+
+	_, err = verifyTemplate(ts.URL+"/bad/rack.json", pub)
+	assert.EqualError(t, err, "template signature is invalid: "+ts.URL+"/bad/rack.json")
+
+	_, err = verifyTemplate(ts.URL+"/junk/rack.json", pub)
+	assert.EqualError(t, err, "template signature is malformed: "+ts.URL+"/junk/rack.json.sig")
+
+	_, err = verifyTemplate(ts.URL+"/unsigned/rack.json", pub)
+	assert.EqualError(t, err, "could not fetch template signature: "+ts.URL+"/unsigned/rack.json.sig: 404 Not Found")
+
+	other, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	_, err = verifyTemplate(ts.URL+"/good/rack.json", other)
+	assert.Error(t, err)
+}
+
+func TestReadSignatureKey(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "signature")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "key.pem")
+
+	require.NoError(t, ioutil.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+
+	key, err := readSignatureKey(file)
+	require.NoError(t, err)
+	assert.Equal(t, pub, key)
+
+	require.NoError(t, ioutil.WriteFile(file, []byte("nope"), 0600))
+
+	_, err = readSignatureKey(file)
+	assert.EqualError(t, err, "no pem encoded public key in "+file)
+}
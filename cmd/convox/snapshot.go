@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/convox/rack/cmd/convox/helpers"
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var offlineFlag = cli.BoolFlag{
+	Name:   "offline",
+	EnvVar: "CONVOX_OFFLINE",
+	Usage:  "show the last cached state instead of contacting the rack",
+}
+
+type snapshot struct {
+	Data json.RawMessage `json:"data"`
+	Time time.Time       `json:"time"`
+}
+
+// fetchSnapshot runs fetch to populate v and caches the result. When --offline is set or the
+// rack can not be reached v is populated from the cache instead.
+func fetchSnapshot(c *cli.Context, kind string, v interface{}, fetch func() error) error {
+	rack := helpers.Coalesce(currentRack(c), "default")
+
+	var ferr error
+
+	if !c.Bool("offline") {
+		ferr = fetch()
+		if ferr == nil {
+			snapshotSave(rack, kind, v)
+			return nil
+		}
+
+		if _, ok := ferr.(net.Error); !ok {
+			return ferr
+		}
+	}
+
+	t, err := snapshotLoad(rack, kind, v)
+	if err != nil {
+		// without a cached copy the rack being unreachable is the problem worth reporting
+		if ferr != nil {
+			return ferr
+		}
+
+		return err
+	}
+
+	stdcli.DefaultWriter.Stderr.Write([]byte(stdcli.Sprintf("<warn>Showing cached %s for %s from %s</warn>\n", kind, rack, t.Format(time.RFC3339))))
+
+	return nil
+}
+
+func snapshotFile(rack, kind string) string {
+	return filepath.Join(ConfigRoot, "snapshots", strings.Replace(rack, "/", "-", -1), fmt.Sprintf("%s.json", kind))
+}
+
+func snapshotLoad(rack, kind string, v interface{}) (time.Time, error) {
+	data, err := ioutil.ReadFile(snapshotFile(rack, kind))
+	if os.IsNotExist(err) {
+		return time.Time{}, fmt.Errorf("no cached %s for rack: %s", kind, rack)
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var s snapshot
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return time.Time{}, err
+	}
+
+	if err := json.Unmarshal(s.Data, v); err != nil {
+		return time.Time{}, err
+	}
+
+	return s.Time, nil
+}
+
+func snapshotSave(rack, kind string, v interface{}) error {
+	file := snapshotFile(rack, kind)
+
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	data, err = json.Marshal(snapshot{Data: data, Time: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(file, data, 0600)
+}
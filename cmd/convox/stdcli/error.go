@@ -1,5 +1,20 @@
 package stdcli
 
+import (
+	"net"
+	"net/url"
+)
+
+// Exit codes so scripts can tell why a command failed
+const (
+	ExitError           = 1 // any other failure
+	ExitUsage           = 2 // missing arguments, unknown commands or flags
+	ExitAuth            = 3 // not logged in or the credentials were refused
+	ExitUnreachable     = 4 // the rack could not be reached
+	ExitUpdateAvailable = 5 // rack update --check found a newer version
+	ExitNotRunning      = 6 // rack --check found the rack is not running
+)
+
 // ErrorStdCli represents a generic stdcli error
 type ErrorStdCli string
 
@@ -7,3 +22,48 @@ type ErrorStdCli string
 func (e ErrorStdCli) Error() string {
 	return string(e)
 }
+
+// ErrorCode is a reported error that exits with a code other than ExitError
+type ErrorCode struct {
+	ErrorStdCli
+	Code int
+}
+
+type exitError struct {
+	error
+	code int
+}
+
+// ExitWith makes the cli exit with code when err is returned from a command
+func ExitWith(code int, err error) error {
+	return exitError{error: err, code: code}
+}
+
+// Exit ends a command with code without reporting anything further
+func Exit(code int) error {
+	return ErrorCode{Code: code}
+}
+
+// ExitCode is the code the cli should exit with for err
+func ExitCode(err error) int {
+	switch t := err.(type) {
+	case nil:
+		return 0
+	case ErrorCode:
+		return t.Code
+	case exitError:
+		return t.code
+	case interface {
+		Unauthorized() bool
+	}:
+		if t.Unauthorized() {
+			return ExitAuth
+		}
+	case *url.Error:
+		return ExitCode(t.Err)
+	case net.Error:
+		return ExitUnreachable
+	}
+
+	return ExitError
+}
@@ -0,0 +1,26 @@
+package stdcli
+
+import (
+	"fmt"
+)
+
+// Spin displays the spinner with a message while fn runs. The spinner is written to stderr
+// and only when Progress allows it so it never ends up in piped output.
+func Spin(message string, fn func() error) error {
+	if !Progress() {
+		return fn()
+	}
+
+	Spinner.Prefix = fmt.Sprintf("%s... ", message)
+	Spinner.Writer = DefaultWriter.Stderr
+	Spinner.Start()
+
+	err := fn()
+
+	Spinner.Stop()
+
+	// make sure no partial frame is left behind on the line
+	fmt.Fprint(DefaultWriter.Stderr, "\r\033[K")
+
+	return err
+}
@@ -101,10 +101,18 @@ func New() *cli.App {
 	app.Commands = Commands
 
 	app.CommandNotFound = func(c *cli.Context, cmd string) {
+		if s := Suggest(c.App.Commands, cmd); s != "" {
+			fmt.Fprintf(os.Stderr, "No such command \"%s\", did you mean \"%s\"? Try `%s --help`\n", cmd, s, Binary)
+			os.Exit(ExitUsage)
+		}
+
 		fmt.Fprintf(os.Stderr, "No such command \"%s\". Try `%s --help`\n", cmd, Binary)
-		os.Exit(1)
+		os.Exit(ExitUsage)
 	}
 
+	app.OnUsageError = usageError
+	setUsageError(app.Commands)
+
 	cli.HelpFlag = cli.BoolFlag{
 		Name:  "help, h",
 		Usage: "show help",
@@ -116,6 +124,28 @@ func New() *cli.App {
 	return app
 }
 
+// usageError shows help for a command given flags it can not parse, like cli does by default,
+// and makes the cli exit with ExitUsage
+func usageError(c *cli.Context, err error, isSubcommand bool) error {
+	fmt.Fprintln(c.App.Writer, "Incorrect Usage:", err.Error())
+	fmt.Fprintln(c.App.Writer)
+
+	if c.Command.Name != "" {
+		cli.ShowCommandHelp(c, c.Command.Name)
+	} else {
+		cli.ShowAppHelp(c)
+	}
+
+	return ExitWith(ExitUsage, err)
+}
+
+func setUsageError(commands []cli.Command) {
+	for i := range commands {
+		commands[i].OnUsageError = usageError
+		setUsageError(commands[i].Subcommands)
+	}
+}
+
 // ValidatePreconditions runs one or more cli.BeforeFuncs where called in Command.Before
 func ValidatePreconditions(preconditions ...cli.BeforeFunc) cli.BeforeFunc {
 	return func(c *cli.Context) error {
@@ -277,6 +307,17 @@ func QOSEventSend(system, id string, ep QOSEventProperties) error {
 	return nil
 }
 
+// Progress tells you if in-place progress like spinners and progress bars can be shown, which
+// needs both stdout and stderr to be terminals. Set CONVOX_NO_PROGRESS to always get plain
+// line by line output, for example in ci logs that capture a pseudo terminal
+func Progress() bool {
+	if os.Getenv("CONVOX_NO_PROGRESS") != "" {
+		return false
+	}
+
+	return IsTerminal(os.Stdout) && IsTerminal(os.Stderr)
+}
+
 // IsTerminal tells you if a given file descriptor has a tty on the other side
 func IsTerminal(f *os.File) bool {
 	stat, err := f.Stat()
@@ -292,7 +333,7 @@ func IsTerminal(f *os.File) bool {
 // Usage prints help for the current command and exits
 func Usage(c *cli.Context) {
 	cli.ShowCommandHelp(c, c.Command.Name)
-	Exiter(129)
+	Exiter(ExitUsage)
 }
 
 func runExecCommand(bin string, args ...string) error {
@@ -448,6 +489,15 @@ func NeedArg(c *cli.Context, count int) {
 	}
 
 	if len(args) > count {
+		// a command group running its own action got an unknown subcommand, suggest a close one
+		if count == 0 && c.Command.Name == "" {
+			if s := Suggest(c.App.Commands, args[0]); s != "" {
+				Errorf("no such command \"%s %s\", did you mean \"%s %s\"?", c.App.Name, args[0], c.App.Name, s)
+				Usage(c)
+				return
+			}
+		}
+
 		msg := fmt.Sprintf("expected %d %s %s; got %d %s (%s).",
 			count,
 			expected,
@@ -461,7 +511,7 @@ func NeedArg(c *cli.Context, count int) {
 			msg += " Perhaps you meant to use a subcommand or option?"
 		}
 		if Debug() {
-			Errorf(msg)
+			Errorf("%s", msg)
 		}
 
 		Usage(c)
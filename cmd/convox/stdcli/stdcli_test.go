@@ -1,9 +1,14 @@
 package stdcli_test
 
 import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
 	"os"
 	"testing"
 
+	"github.com/convox/rack/client"
 	"github.com/convox/rack/cmd/convox/stdcli"
 	"github.com/convox/rack/test"
 	"github.com/stretchr/testify/assert"
@@ -96,3 +101,117 @@ func TestStdcliApp(t *testing.T) {
 	stdcli.Spinner.Stop()
 	assert.Equal(t, stdcli.Spinner.Prefix, "Testing...")
 }
+
+func TestSpin(t *testing.T) {
+	ran := false
+
+	err := stdcli.Spin("Testing", func() error {
+		ran = true
+		return fmt.Errorf("spin error")
+	})
+
+	assert.True(t, ran)
+	assert.EqualError(t, err, "spin error")
+}
+
+func TestSuggest(t *testing.T) {
+	commands := []cli.Command{
+		{Name: "install"},
+		{Name: "logs"},
+		{Name: "params"},
+		{Name: "ps"},
+		{Name: "update", Aliases: []string{"upgrade"}},
+		{Name: "secret", Hidden: true},
+	}
+
+	for in, out := range map[string]string{
+		"psx":     "ps",
+		"lgos":    "logs",
+		"parms":   "params",
+		"instal":  "install",
+		"upgrdae": "upgrade",
+		"udpate":  "update",
+		"secre":   "",
+		"scale":   "",
+		"zzzz":    "",
+	} {
+		assert.Equal(t, out, stdcli.Suggest(commands, in), in)
+	}
+
+	assert.Equal(t, []string{"InstanceType", "InstanceTypes"}, stdcli.Similar([]string{"Private", "InstanceTypes", "InstanceType"}, "instancetype"))
+	assert.Equal(t, []string{}, stdcli.Similar([]string{"Private"}, "Bogus"))
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox rack psx",
+			Exit:    2,
+			Stderr:  "ERROR: no such command \"convox rack psx\", did you mean \"convox rack ps\"?\n",
+		},
+	)
+}
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, 0, stdcli.ExitCode(nil))
+	assert.Equal(t, stdcli.ExitError, stdcli.ExitCode(fmt.Errorf("failed")))
+	assert.Equal(t, stdcli.ExitAuth, stdcli.ExitCode(stdcli.ExitWith(stdcli.ExitAuth, fmt.Errorf("please login"))))
+	assert.Equal(t, stdcli.ExitAuth, stdcli.ExitCode(client.AuthError("response status: 401")))
+	assert.Equal(t, stdcli.ExitUpdateAvailable, stdcli.ExitCode(stdcli.Exit(stdcli.ExitUpdateAvailable)))
+
+	_, err := http.Get("http://127.0.0.1:1")
+	assert.Equal(t, stdcli.ExitUnreachable, stdcli.ExitCode(err))
+
+	// the code survives reporting the error
+	assert.Equal(t, stdcli.ExitUnreachable, stdcli.ExitCode(stdcli.Error(err)))
+	assert.Equal(t, stdcli.ExitError, stdcli.ExitCode(stdcli.Error(fmt.Errorf("failed"))))
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox apps",
+			Env:     map[string]string{"CONVOX_HOST": "127.0.0.1:1", "CONVOX_PASSWORD": "test"},
+			Exit:    stdcli.ExitUnreachable,
+		},
+	)
+}
+
+func TestOutputJSON(t *testing.T) {
+	assert.True(t, stdcli.OutputJSON([]string{"rack", "ps", "--output", "json"}))
+	assert.True(t, stdcli.OutputJSON([]string{"rack", "ps", "--output=json"}))
+	assert.False(t, stdcli.OutputJSON([]string{"rack", "ps", "--output", "text"}))
+	assert.False(t, stdcli.OutputJSON([]string{"run", "web", "--", "cmd", "--output", "json"}))
+	assert.False(t, stdcli.OutputJSON([]string{"rack", "ps"}))
+
+	os.Setenv("CONVOX_OUTPUT", "json")
+	defer os.Unsetenv("CONVOX_OUTPUT")
+
+	assert.True(t, stdcli.OutputJSON([]string{"rack", "ps"}))
+	assert.False(t, stdcli.OutputJSON([]string{"rack", "ps", "--output", "text"}))
+}
+
+func TestErrorJSON(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := &stdcli.Writer{JSONErrors: true, Stderr: &buf}
+
+	err := w.Error(stdcli.ExitWith(stdcli.ExitAuth, fmt.Errorf("please login")))
+
+	assert.Equal(t, "{\"code\":3,\"error\":\"please login\"}\n", buf.String())
+	assert.Equal(t, stdcli.ExitAuth, stdcli.ExitCode(err))
+
+	temp, _ := ioutil.TempDir("", "convox-test")
+	defer os.RemoveAll(temp)
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox rack ps --output json",
+			Env:     map[string]string{"CONVOX_CONFIG": temp, "CONVOX_HOST": "127.0.0.1:1", "CONVOX_PASSWORD": "test"},
+			Exit:    stdcli.ExitUnreachable,
+			Stderr:  "{\"code\":4,\"error\":",
+		},
+		test.ExecRun{
+			Command: "convox rack ps --offline --output json",
+			Env:     map[string]string{"CONVOX_CONFIG": temp, "CONVOX_HOST": "127.0.0.1:1", "CONVOX_PASSWORD": "test"},
+			Exit:    stdcli.ExitError,
+			Stderr:  "{\"code\":1,\"error\":\"no cached processes for rack: default\"}\n",
+		},
+	)
+}
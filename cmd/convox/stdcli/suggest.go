@@ -0,0 +1,106 @@
+package stdcli
+
+import (
+	"sort"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Suggest returns the visible command name closest to input, or "" if none is close enough to
+// be a likely typo
+func Suggest(commands []cli.Command, input string) string {
+	names := []string{}
+
+	for _, c := range commands {
+		if c.Hidden || c.Name == "help" {
+			continue
+		}
+
+		names = append(names, c.Names()...)
+	}
+
+	if similar := Similar(names, input); len(similar) > 0 {
+		return similar[0]
+	}
+
+	return ""
+}
+
+// Similar returns the names close enough to input to be a likely typo, closest first. Case is
+// ignored so mixed case names like rack parameters still match
+func Similar(names []string, input string) []string {
+	sorted := append([]string{}, names...)
+	sort.Strings(sorted)
+
+	distances := map[string]int{}
+	similar := []string{}
+
+	for _, name := range sorted {
+		d := levenshtein(strings.ToLower(input), strings.ToLower(name))
+
+		// allow one edit for very short names and two for the rest
+		max := 1
+		if len(name) > 3 {
+			max = 2
+		}
+
+		if d > max {
+			continue
+		}
+
+		if _, ok := distances[name]; !ok {
+			similar = append(similar, name)
+		}
+
+		distances[name] = d
+	}
+
+	sort.SliceStable(similar, func(i, j int) bool {
+		return distances[similar[i]] < distances[similar[j]]
+	})
+
+	return similar
+}
+
+// levenshtein is the number of single character insertions, deletions and substitutions needed
+// to turn a into b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func minInt(first int, rest ...int) int {
+	m := first
+
+	for _, n := range rest {
+		if n < m {
+			m = n
+		}
+	}
+
+	return m
+}
@@ -1,10 +1,12 @@
 package stdcli
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"regexp"
+	"strings"
 )
 
 var (
@@ -14,10 +16,11 @@ var (
 type Renderer func(string) string
 
 type Writer struct {
-	Color  bool
-	Stdout io.Writer
-	Stderr io.Writer
-	Tags   map[string]Renderer
+	Color      bool
+	JSONErrors bool
+	Stdout     io.Writer
+	Stderr     io.Writer
+	Tags       map[string]Renderer
 }
 
 func init() {
@@ -75,11 +78,46 @@ func Writef(format string, args ...interface{}) (int, error) {
 }
 
 func (w *Writer) Error(err error) error {
-	err = ErrorStdCli(err.Error())
-	if err.Error() != "Token expired" {
-		w.Stderr.Write([]byte(fmt.Sprintf(w.renderTags("<error>%s</error>\n"), err)))
+	code := ExitCode(err)
+
+	e := ErrorStdCli(err.Error())
+	switch {
+	case e.Error() == "Token expired":
+	case w.JSONErrors:
+		data, _ := json.Marshal(map[string]interface{}{"error": e.Error(), "code": code})
+		w.Stderr.Write(append(data, '\n'))
+	default:
+		w.Stderr.Write([]byte(fmt.Sprintf(w.renderTags("<error>%s</error>\n"), e)))
 	}
-	return err
+
+	if code > ExitError {
+		return ErrorCode{ErrorStdCli: e, Code: code}
+	}
+
+	return e
+}
+
+// OutputJSON reports whether a command line asks for json output with --output json, or
+// CONVOX_OUTPUT=json, so errors can be written as json before any flags are parsed
+func OutputJSON(args []string) bool {
+	for i, a := range args {
+		if a == "--" {
+			break
+		}
+
+		if !strings.HasPrefix(a, "-") {
+			continue
+		}
+
+		switch name := strings.TrimLeft(a, "-"); {
+		case strings.HasPrefix(name, "output="):
+			return strings.TrimPrefix(name, "output=") == "json"
+		case name == "output" && i+1 < len(args):
+			return args[i+1] == "json"
+		}
+	}
+
+	return os.Getenv("CONVOX_OUTPUT") == "json"
 }
 
 func (w *Writer) Errorf(format string, args ...interface{}) error {
@@ -28,17 +28,17 @@ func TestUninstall(t *testing.T) {
 		test.ExecRun{
 			Command:  "convox uninstall",
 			OutMatch: "convox uninstall: uninstall a convox rack",
-			Exit:     129,
+			Exit:     2,
 		},
 		test.ExecRun{
 			Command:  "convox uninstall onlyOneArgument",
 			OutMatch: "convox uninstall: uninstall a convox rack",
-			Exit:     129,
+			Exit:     2,
 		},
 		test.ExecRun{
 			Command:  "convox uninstall more than three arguments",
 			OutMatch: "convox uninstall: uninstall a convox rack",
-			Exit:     129,
+			Exit:     2,
 		},
 	}
 
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/convox/rack/cmd/convox/stdcli"
+	"github.com/convox/version"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// versionsCacheTTL is how long the published rack versions are reused before fetching them again
+const versionsCacheTTL = 1 * time.Hour
+
+var refreshFlag = cli.BoolFlag{
+	Name:  "refresh",
+	Usage: "fetch the list of rack versions instead of using the copy cached for up to an hour",
+}
+
+// rackVersions lists the published rack versions, fetching them at most once an hour unless
+// refresh is set. When they can not be fetched the cached list is used however old it is.
+func rackVersions(refresh bool) (version.Versions, error) {
+	var cached version.Versions
+
+	t, cerr := versionsCacheLoad(&cached)
+
+	if cerr == nil && !refresh && time.Since(t) < versionsCacheTTL {
+		return cached, nil
+	}
+
+	vs, err := version.All()
+	if err == nil {
+		versionsCacheSave(vs)
+		return vs, nil
+	}
+
+	if cerr != nil {
+		return nil, err
+	}
+
+	stdcli.Warn(fmt.Sprintf("could not fetch rack versions, using the list cached at %s: %s", t.Format(time.RFC3339), err))
+
+	return cached, nil
+}
+
+func versionsCacheFile() string {
+	return filepath.Join(ConfigRoot, "cache", "versions.json")
+}
+
+func versionsCacheLoad(vs *version.Versions) (time.Time, error) {
+	data, err := ioutil.ReadFile(versionsCacheFile())
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var s snapshot
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return time.Time{}, err
+	}
+
+	if err := json.Unmarshal(s.Data, vs); err != nil {
+		return time.Time{}, err
+	}
+
+	return s.Time, nil
+}
+
+func versionsCacheSave(vs version.Versions) error {
+	file := versionsCacheFile()
+
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(vs)
+	if err != nil {
+		return err
+	}
+
+	data, err = json.Marshal(snapshot{Data: data, Time: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(file, data, 0600)
+}
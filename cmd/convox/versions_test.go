@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/convox/rack/client"
+	"github.com/convox/rack/test"
+	"github.com/convox/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRackVersionsCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "convox-config")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	root, url := ConfigRoot, version.URL
+
+	defer func() { ConfigRoot, version.URL = root, url }()
+
+	ConfigRoot = dir
+
+	fetches := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		json.NewEncoder(w).Encode(version.Versions{{Version: "20170201000000", Published: true}})
+	}))
+
+	version.URL = ts.URL
+
+	vs, err := rackVersions(false)
+	require.NoError(t, err)
+	assert.Equal(t, "20170201000000", vs[0].Version)
+
+	_, err = rackVersions(false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fetches)
+
+	_, err = rackVersions(true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, fetches)
+
+	ts.Close()
+
+	// a stale cache is still better than nothing when the versions can not be fetched
+	data, err := json.Marshal(version.Versions{{Version: "20170101000000", Published: true}})
+	require.NoError(t, err)
+
+	data, err = json.Marshal(snapshot{Data: data, Time: time.Now().Add(-2 * versionsCacheTTL)})
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(versionsCacheFile(), data, 0600))
+
+	vs, err = rackVersions(false)
+	require.NoError(t, err)
+	assert.Equal(t, "20170101000000", vs[0].Version)
+
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "cache")))
+
+	_, err = rackVersions(false)
+	assert.Error(t, err)
+}
+
+func TestRackReleasesCachedVersions(t *testing.T) {
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/system", Code: 200, Response: client.System{
+			Name:    "convox",
+			Status:  "running",
+			Version: "20170101000000",
+		}},
+		test.Http{Method: "GET", Path: "/system/releases", Code: 200, Response: client.Releases{
+			client.Release{Id: "20170101000000"},
+		}},
+	)
+
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "convox-config")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	root := ConfigRoot
+
+	defer func() { ConfigRoot = root }()
+
+	ConfigRoot = dir
+
+	require.NoError(t, versionsCacheSave(version.Versions{
+		{Version: "20170101000000", Published: true},
+		{Version: "20170201000000", Published: true},
+	}))
+
+	test.Runs(t,
+		test.ExecRun{
+			Command:  "convox rack releases",
+			Env:      map[string]string{"CONVOX_CONFIG": dir},
+			Exit:     0,
+			OutMatch: "New version available: 20170201000000\n",
+		},
+	)
+}
+
+func TestRackReleasesJSON(t *testing.T) {
+	created := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	ts := testServer(t,
+		test.Http{Method: "GET", Path: "/system", Code: 200, Response: client.System{
+			Name:    "convox",
+			Status:  "running",
+			Version: "20170101000000",
+		}},
+		test.Http{Method: "GET", Path: "/system/releases", Code: 200, Response: client.Releases{
+			client.Release{Id: "20170101000000", Created: created},
+			client.Release{Id: "20161201000000", Created: created.AddDate(0, -1, 0)},
+		}},
+	)
+
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "convox-config")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	root := ConfigRoot
+
+	defer func() { ConfigRoot = root }()
+
+	ConfigRoot = dir
+
+	require.NoError(t, versionsCacheSave(version.Versions{
+		{Version: "20161201000000", Published: true},
+		{Version: "20170101000000", Published: true},
+		{Version: "20170201000000", Published: true},
+	}))
+
+	cmd := exec.Command("convox", "rack", "releases", "--output", "json")
+	cmd.Env = append(os.Environ(), "CONVOX_CONFIG="+dir)
+
+	data, err := cmd.Output()
+	require.NoError(t, err)
+
+	var out rackReleases
+
+	require.NoError(t, json.Unmarshal(data, &out))
+
+	assert.Equal(t, rackReleases{
+		Available: "20170201000000",
+		Releases: []rackRelease{
+			{Created: "2017-01-01T12:00:00Z", Id: "20170101000000", Status: "active"},
+			{Created: "2016-12-01T12:00:00Z", Id: "20161201000000"},
+		},
+	}, out)
+
+	test.Runs(t,
+		test.ExecRun{
+			Command: "convox rack releases --output yaml",
+			Exit:    1,
+			Stderr:  "unknown output format: yaml",
+		},
+	)
+}
@@ -472,6 +472,7 @@ func appFromStack(stack *cloudformation.Stack) structs.App {
 		Generation: coalesces(stackTags(stack)["Generation"], "1"),
 		Release:    coalesces(stackOutputs(stack)["Release"], stackParameters(stack)["Release"]),
 		Status:     humanStatus(*stack.StackStatus),
+		Version:    coalesces(stackParameters(stack)["Version"], tags["Version"]),
 		Outputs:    stackOutputs(stack),
 		Parameters: stackParameters(stack),
 		Tags:       stackTags(stack),
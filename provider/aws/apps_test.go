@@ -42,6 +42,7 @@ func TestAppGet(t *testing.T) {
 		Name:       "httpd",
 		Release:    "RVFETUHHKKD",
 		Status:     "running",
+		Version:    "20160330143438-command-exec-form",
 		Outputs: map[string]string{
 			"BalancerWebHost":       "httpd-web-7E5UPCM-1241527783.us-east-1.elb.amazonaws.com",
 			"Kinesis":               "convox-httpd-Kinesis-1MAP0GJ6RITJF",
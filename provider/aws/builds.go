@@ -810,10 +810,11 @@ func (p *AWSProvider) runBuild(build *structs.Build, method, url string, opts st
 	rackUrl := fmt.Sprintf("https://%s@%s", p.Password, stackOutputs(rk)["Dashboard"])
 
 	req := &ecs.RunTaskInput{
-		Cluster:        aws.String(p.BuildCluster),
-		Count:          aws.Int64(1),
-		StartedBy:      aws.String(fmt.Sprintf("convox.%s", build.App)),
-		TaskDefinition: aws.String(td),
+		Cluster:              aws.String(p.BuildCluster),
+		Count:                aws.Int64(1),
+		PlacementConstraints: uncordoned(),
+		StartedBy:            aws.String(fmt.Sprintf("convox.%s", build.App)),
+		TaskDefinition:       aws.String(td),
 		Overrides: &ecs.TaskOverride{
 			ContainerOverrides: []*ecs.ContainerOverride{
 				{
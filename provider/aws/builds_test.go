@@ -1144,6 +1144,12 @@ var cycleBuildRunTask = awsutil.Cycle{
 		Body: `{
 			"cluster": "cluster-test",
 			"count": 1,
+			"placementConstraints": [
+				{
+					"expression": "attribute:convox.cordoned !exists",
+					"type": "memberOf"
+				}
+			],
 			"overrides": {
 				"containerOverrides": [
 					{
@@ -1224,6 +1230,12 @@ var cycleBuildRunTaskCluster = awsutil.Cycle{
 		Body: `{
 			"cluster": "cluster-build",
 			"count": 1,
+			"placementConstraints": [
+				{
+					"expression": "attribute:convox.cordoned !exists",
+					"type": "memberOf"
+				}
+			],
 			"overrides": {
 				"containerOverrides": [
 					{
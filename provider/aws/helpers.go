@@ -969,6 +969,25 @@ func (p *AWSProvider) taskDefinitionRelease(arn string) (string, error) {
 	return *release, nil
 }
 
+// templateClient fetches published templates, a stalled download fails instead of holding up an update
+var templateClient = &http.Client{Timeout: 60 * time.Second}
+
+// fetchTemplate downloads a published template
+func fetchTemplate(url string) ([]byte, error) {
+	res, err := templateClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return nil, fmt.Errorf("could not fetch template %s: %s", url, res.Status)
+	}
+
+	return ioutil.ReadAll(res.Body)
+}
+
 // updateStack updates a stack
 //   template is url to a template or empty string to reuse previous
 //   changes is a list of parameter changes to make (does not need to include every param)
@@ -1020,8 +1039,16 @@ func (p *AWSProvider) updateStack(name string, template string, changes map[stri
 			}
 
 			req.TemplateURL = aws.String(ru)
-		} else {
-			res, err := http.Get(template)
+		} else if strings.HasPrefix(template, "s3://") {
+			u, err := url.Parse(template)
+			if err != nil {
+				return err
+			}
+
+			res, err := p.s3().GetObject(&s3.GetObjectInput{
+				Bucket: aws.String(u.Host),
+				Key:    aws.String(strings.TrimPrefix(u.Path, "/")),
+			})
 			if err != nil {
 				return err
 			}
@@ -1032,6 +1059,13 @@ func (p *AWSProvider) updateStack(name string, template string, changes map[stri
 				return err
 			}
 
+			req.TemplateURL = aws.String(fmt.Sprintf("https://s3.%s.amazonaws.com/%s%s", p.Region, u.Host, u.Path))
+		} else {
+			data, err = fetchTemplate(template)
+			if err != nil {
+				return err
+			}
+
 			req.TemplateURL = aws.String(template)
 		}
 
@@ -11,10 +11,38 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/convox/rack/structs"
 	"golang.org/x/crypto/ssh"
 )
 
+// InstanceCordon marks an instance so that no new processes are placed on it,
+// existing processes keep running. Services only pick up the placement constraint
+// from the app template, so apps deployed before it existed need a redeploy.
+func (p *AWSProvider) InstanceCordon(id string) error {
+	arn, err := p.containerInstanceArn(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.ecs().PutAttributes(&ecs.PutAttributesInput{
+		Attributes: []*ecs.Attribute{
+			{
+				Name:       aws.String(cordonAttribute),
+				TargetId:   aws.String(arn),
+				TargetType: aws.String("container-instance"),
+				Value:      aws.String("true"),
+			},
+		},
+		Cluster: aws.String(p.Cluster),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (p *AWSProvider) InstanceKeyroll() error {
 	key := fmt.Sprintf("%s-keypair-%d", os.Getenv("RACK"), (rand.Intn(8999) + 1000))
 
@@ -50,12 +78,19 @@ func (p *AWSProvider) InstanceList() (structs.Instances, error) {
 	err := p.ec2().DescribeInstancesPages(req, func(res *ec2.DescribeInstancesOutput, last bool) bool {
 		for _, r := range res.Reservations {
 			for _, i := range r.Instances {
+				zone := ""
+				if i.Placement != nil {
+					zone = cs(i.Placement.AvailabilityZone, "")
+				}
+
 				ihash[cs(i.InstanceId, "")] = structs.Instance{
 					Id:        cs(i.InstanceId, ""),
 					PrivateIp: cs(i.PrivateIpAddress, ""),
 					PublicIp:  cs(i.PublicIpAddress, ""),
 					Status:    "",
 					Started:   ct(i.LaunchTime),
+					Type:      cs(i.InstanceType, ""),
+					Zone:      zone,
 				}
 			}
 		}
@@ -75,6 +110,7 @@ func (p *AWSProvider) InstanceList() (structs.Instances, error) {
 		i := ihash[id]
 
 		i.Agent = cb(cci.AgentConnected, false)
+		i.Cordoned = containerInstanceCordoned(cci)
 		i.Processes = int(ci(cci.RunningTasksCount, 0))
 		i.Status = strings.ToLower(cs(cci.Status, "unknown"))
 
@@ -249,6 +285,69 @@ func (p *AWSProvider) InstanceTerminate(id string) error {
 	return nil
 }
 
+func (p *AWSProvider) InstanceUncordon(id string) error {
+	arn, err := p.containerInstanceArn(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.ecs().DeleteAttributes(&ecs.DeleteAttributesInput{
+		Attributes: []*ecs.Attribute{
+			{
+				Name:       aws.String(cordonAttribute),
+				TargetId:   aws.String(arn),
+				TargetType: aws.String("container-instance"),
+			},
+		},
+		Cluster: aws.String(p.Cluster),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// cordonAttribute is set on cordoned container instances, app services, one-off
+// processes and builds are constrained to instances without it
+const cordonAttribute = "convox.cordoned"
+
+// uncordoned keeps a task off cordoned container instances
+func uncordoned() []*ecs.PlacementConstraint {
+	return []*ecs.PlacementConstraint{
+		{
+			Expression: aws.String(fmt.Sprintf("attribute:%s !exists", cordonAttribute)),
+			Type:       aws.String("memberOf"),
+		},
+	}
+}
+
+// containerInstanceArn finds the container instance for an ec2 instance in this rack
+func (p *AWSProvider) containerInstanceArn(id string) (string, error) {
+	cis, err := p.listAndDescribeContainerInstances()
+	if err != nil {
+		return "", err
+	}
+
+	for _, ci := range cis.ContainerInstances {
+		if cs(ci.Ec2InstanceId, "") == id {
+			return cs(ci.ContainerInstanceArn, ""), nil
+		}
+	}
+
+	return "", fmt.Errorf("no such instance: %s", id)
+}
+
+func containerInstanceCordoned(ci *ecs.ContainerInstance) bool {
+	for _, a := range ci.Attributes {
+		if cs(a.Name, "") == cordonAttribute {
+			return true
+		}
+	}
+
+	return false
+}
+
 type instanceResource struct {
 	Total int `json:"total"`
 	Free  int `json:"free"`
@@ -35,6 +35,8 @@ func TestInstancesList(t *testing.T) {
 			PublicIp:  "54.85.115.31",
 			Status:    "active",
 			Started:   time.Unix(1448386549, 0).UTC(),
+			Type:      "t2.small",
+			Zone:      "us-east-1b",
 		},
 		structs.Instance{
 			Agent:     true,
@@ -46,6 +48,8 @@ func TestInstancesList(t *testing.T) {
 			PublicIp:  "54.208.61.75",
 			Status:    "active",
 			Started:   time.Unix(1448484072, 0).UTC(),
+			Type:      "t2.small",
+			Zone:      "us-east-1a",
 		},
 		structs.Instance{
 			Agent:     true,
@@ -57,6 +61,8 @@ func TestInstancesList(t *testing.T) {
 			PublicIp:  "52.71.252.224",
 			Status:    "active",
 			Started:   time.Unix(1447901993, 0).UTC(),
+			Type:      "t2.small",
+			Zone:      "us-east-1c",
 		},
 	}, is)
 }
@@ -417,10 +417,11 @@ func (p *AWSProvider) ProcessRun(app string, opts structs.ProcessRunOptions) (st
 	}
 
 	req := &ecs.RunTaskInput{
-		Cluster:        aws.String(p.Cluster),
-		Count:          aws.Int64(1),
-		StartedBy:      aws.String(fmt.Sprintf("convox.%s", app)),
-		TaskDefinition: aws.String(td),
+		Cluster:              aws.String(p.Cluster),
+		Count:                aws.Int64(1),
+		PlacementConstraints: uncordoned(),
+		StartedBy:            aws.String(fmt.Sprintf("convox.%s", app)),
+		TaskDefinition:       aws.String(td),
 	}
 
 	if opts.Command != nil {
@@ -1114,10 +1115,11 @@ func (p *AWSProvider) processRunAttached(app string, opts structs.ProcessRunOpti
 	}
 
 	req := &ecs.RunTaskInput{
-		Cluster:        aws.String(p.Cluster),
-		Count:          aws.Int64(1),
-		StartedBy:      aws.String(fmt.Sprintf("convox.%s", app)),
-		TaskDefinition: aws.String(td),
+		Cluster:              aws.String(p.Cluster),
+		Count:                aws.Int64(1),
+		PlacementConstraints: uncordoned(),
+		StartedBy:            aws.String(fmt.Sprintf("convox.%s", app)),
+		TaskDefinition:       aws.String(td),
 	}
 
 	if opts.Command != nil {
@@ -1280,6 +1280,12 @@ var cycleProcessRunTaskAttached = awsutil.Cycle{
 		Body: `{
 			"cluster": "cluster-test",
 			"count": 1,
+			"placementConstraints": [
+				{
+					"expression": "attribute:convox.cordoned !exists",
+					"type": "memberOf"
+				}
+			],
 			"overrides": {
 				"containerOverrides": [
 					{
@@ -1328,6 +1334,12 @@ var cycleProcessRunTaskDetached = awsutil.Cycle{
 		Body: `{
 			"cluster": "cluster-test",
 			"count": 1,
+			"placementConstraints": [
+				{
+					"expression": "attribute:convox.cordoned !exists",
+					"type": "memberOf"
+				}
+			],
 			"overrides": {
 				"containerOverrides": [
 					{
@@ -1,6 +1,9 @@
 package aws
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -13,6 +16,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/convox/rack/structs"
 	"golang.org/x/crypto/nacl/secretbox"
 )
@@ -22,6 +26,9 @@ const (
 	nonceLength = 24
 )
 
+// RackTemplate is the location of the published rack template for a given version
+var RackTemplate = "https://convox.s3.amazonaws.com/release/%s/rack.json"
+
 type envelope struct {
 	Ciphertext   []byte `json:"c"`
 	EncryptedKey []byte `json:"k"`
@@ -199,6 +206,26 @@ func (p *AWSProvider) SystemLogs(opts structs.LogsOptions) (io.ReadCloser, error
 	return p.subscribeLogs(group, opts)
 }
 
+// SystemParameterDefinitions reads the parameters declared in the template the rack stack runs
+func (p *AWSProvider) SystemParameterDefinitions() (structs.ParameterDefinitions, error) {
+	res, err := p.cloudformation().GetTemplate(&cloudformation.GetTemplateInput{
+		StackName: aws.String(p.Rack),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var template struct {
+		Parameters structs.ParameterDefinitions
+	}
+
+	if err := json.Unmarshal([]byte(*res.TemplateBody), &template); err != nil {
+		return nil, err
+	}
+
+	return template.Parameters, nil
+}
+
 func (p *AWSProvider) SystemProcesses(opts structs.SystemProcessesOptions) (structs.Processes, error) {
 	var tasks []string
 	var err error
@@ -277,6 +304,11 @@ func (p *AWSProvider) SystemUninstall(name string, opts structs.SystemUninstallO
 }
 
 func (p *AWSProvider) SystemUpdate(opts structs.SystemUpdateOptions) error {
+	// cloudformation replaces instances in batches but has no way to pause and check health between them
+	if opts.Canary != nil && *opts.Canary {
+		return fmt.Errorf("canary updates are not supported by this rack")
+	}
+
 	changes := map[string]string{}
 	params := opts.Parameters
 	template := ""
@@ -290,17 +322,40 @@ func (p *AWSProvider) SystemUpdate(opts structs.SystemUpdateOptions) error {
 		changes["count"] = strconv.Itoa(*opts.InstanceCount)
 	}
 
+	if opts.InstanceMin != nil {
+		params["InstanceMinCount"] = strconv.Itoa(*opts.InstanceMin)
+		changes["min"] = strconv.Itoa(*opts.InstanceMin)
+	}
+
+	if opts.InstanceMax != nil {
+		params["InstanceMaxCount"] = strconv.Itoa(*opts.InstanceMax)
+		changes["max"] = strconv.Itoa(*opts.InstanceMax)
+	}
+
 	if opts.InstanceType != nil {
 		params["InstanceType"] = *opts.InstanceType
 		changes["type"] = *opts.InstanceType
 	}
 
 	if opts.Version != nil {
-		template = fmt.Sprintf("https://convox.s3.amazonaws.com/release/%s/rack.json", *opts.Version)
+		template = fmt.Sprintf(RackTemplate, *opts.Version)
 		params["Version"] = *opts.Version
 		changes["version"] = *opts.Version
 	}
 
+	if opts.TemplateDigest != nil {
+		if template == "" {
+			return fmt.Errorf("a template digest requires a version")
+		}
+
+		t, err := p.storeVerifiedTemplate(template, *opts.TemplateDigest)
+		if err != nil {
+			return err
+		}
+
+		template = t
+	}
+
 	// if there is a version update then record it
 	if v, ok := changes["version"]; ok {
 		_, err := p.dynamodb().PutItem(&dynamodb.PutItemInput{
@@ -325,3 +380,32 @@ func (p *AWSProvider) SystemUpdate(opts structs.SystemUpdateOptions) error {
 
 	return nil
 }
+
+// storeVerifiedTemplate fetches the template at url, checks it against the sha256 digest the
+// caller verified and keeps a copy in the settings bucket, so the stack is updated with exactly
+// the bytes that were verified even if the url serves something else later
+func (p *AWSProvider) storeVerifiedTemplate(url, digest string) (string, error) {
+	data, err := fetchTemplate(url)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+
+	if hex.EncodeToString(sum[:]) != digest {
+		return "", fmt.Errorf("template %s does not match the verified digest", url)
+	}
+
+	key := fmt.Sprintf("templates/rack/%s.json", digest)
+
+	_, err = p.s3().PutObject(&s3.PutObjectInput{
+		Body:   bytes.NewReader(data),
+		Bucket: aws.String(p.SettingsBucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("s3://%s/%s", p.SettingsBucket, key), nil
+}
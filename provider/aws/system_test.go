@@ -1,6 +1,12 @@
 package aws_test
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -134,6 +140,98 @@ func TestSystemUpdateNewParameter(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestSystemUpdateVerified(t *testing.T) {
+	template := `{"Parameters":{"Ami":{"Type":"String"},"Version":{"Type":"String"}}}`
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(template))
+	}))
+	defer ts.Close()
+
+	defer func(t string) { aws.RackTemplate = t }(aws.RackTemplate)
+	aws.RackTemplate = ts.URL + "/release/%s/rack.json"
+
+	sum := sha256.Sum256([]byte(template))
+	digest := hex.EncodeToString(sum[:])
+	key := fmt.Sprintf("/convox-settings/templates/rack/%s.json", digest)
+
+	provider := StubAwsProvider(
+		awsutil.Cycle{
+			Request:  awsutil.Request{Method: "PUT", RequestURI: key, Body: template},
+			Response: awsutil.Response{StatusCode: 200},
+		},
+		cycleSystemReleasePutItem,
+		cycleSystemDescribeStacksMissingParameters,
+		awsutil.Cycle{
+			Request:  awsutil.Request{Method: "GET", RequestURI: key},
+			Response: awsutil.Response{StatusCode: 200, Body: template},
+		},
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/",
+				Body:       `Action=UpdateStack&Capabilities.member.1=CAPABILITY_IAM&NotificationARNs.member.1=&Parameters.member.1.ParameterKey=Ami&Parameters.member.1.UsePreviousValue=true&Parameters.member.2.ParameterKey=Version&Parameters.member.2.ParameterValue=20171214220445&StackName=convox&TemplateURL=https%3A%2F%2Fs3.us-test-1.amazonaws.com` + url.QueryEscape(key) + `&Version=2010-05-15`,
+			},
+			Response: cycleSystemUpdateStack.Response,
+		},
+		awsutil.Cycle{
+			Request: awsutil.Request{
+				RequestURI: "/",
+				Body:       `Action=Publish&Message=%7B%22action%22%3A%22rack%3Aupdate%22%2C%22data%22%3A%7B%22rack%22%3A%22convox%22%2C%22version%22%3A%2220171214220445%22%7D%2C%22status%22%3A%22success%22%2C%22timestamp%22%3A%220001-01-01T00%3A00%3A00Z%22%7D&Subject=rack%3Aupdate&TargetArn=&Version=2010-03-31`,
+			},
+			Response: cycleSystemUpdateNotificationPublish.Response,
+		},
+	)
+	defer provider.Close()
+
+	err := provider.SystemUpdate(structs.SystemUpdateOptions{
+		TemplateDigest: options.String(digest),
+		Version:        options.String("20171214220445"),
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestSystemUpdateVerifiedMismatch(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Parameters":{"Evil":{}}}`))
+	}))
+	defer ts.Close()
+
+	defer func(t string) { aws.RackTemplate = t }(aws.RackTemplate)
+	aws.RackTemplate = ts.URL + "/release/%s/rack.json"
+
+	provider := StubAwsProvider()
+	defer provider.Close()
+
+	err := provider.SystemUpdate(structs.SystemUpdateOptions{
+		TemplateDigest: options.String("0000"),
+		Version:        options.String("20171214220445"),
+	})
+
+	assert.EqualError(t, err, fmt.Sprintf("template %s/release/20171214220445/rack.json does not match the verified digest", ts.URL))
+
+	err = provider.SystemUpdate(structs.SystemUpdateOptions{
+		TemplateDigest: options.String("0000"),
+	})
+
+	assert.EqualError(t, err, "a template digest requires a version")
+}
+
+func TestSystemParameterDefinitions(t *testing.T) {
+	provider := StubAwsProvider(
+		cycleSystemGetTemplate,
+	)
+	defer provider.Close()
+
+	defs, err := provider.SystemParameterDefinitions()
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, structs.ParameterDefinitions{
+		"InstanceCount": structs.ParameterDefinition{Default: "3", Description: "number of instances", MinValue: "3", Type: "Number"},
+		"Password":      structs.ParameterDefinition{Description: "api password", NoEcho: true, Type: "String"},
+	}, defs)
+}
+
 func TestSystemProcessesList(t *testing.T) {
 	provider := StubAwsProvider(
 		cycleSystemListStackResources,
@@ -361,6 +459,21 @@ var cycleSystemDescribeStacksMissingParameters = awsutil.Cycle{
 	},
 }
 
+var cycleSystemGetTemplate = awsutil.Cycle{
+	awsutil.Request{"POST", "/", "", `Action=GetTemplate&StackName=convox&Version=2010-05-15`},
+	awsutil.Response{
+		200,
+		`<GetTemplateResponse xmlns="http://cloudformation.amazonaws.com/doc/2010-05-15/">
+			<GetTemplateResult>
+				<TemplateBody>{"Parameters":{"InstanceCount":{"Default":"3","Description":"number of instances","MinValue":"3","Type":"Number"},"Password":{"Description":"api password","NoEcho":true,"Type":"String"}}}</TemplateBody>
+			</GetTemplateResult>
+			<ResponseMetadata>
+				<RequestId>b9b4b068-3a41-11e5-94eb-example</RequestId>
+			</ResponseMetadata>
+		</GetTemplateResponse>`,
+	},
+}
+
 var cycleSystemReleaseList = awsutil.Cycle{
 	Request: awsutil.Request{
 		RequestURI: "/",
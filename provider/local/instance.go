@@ -7,6 +7,10 @@ import (
 	"github.com/convox/rack/structs"
 )
 
+func (p *Provider) InstanceCordon(id string) error {
+	return fmt.Errorf("unimplemented")
+}
+
 func (p *Provider) InstanceKeyroll() error {
 	return fmt.Errorf("unimplemented")
 }
@@ -22,3 +26,7 @@ func (p *Provider) InstanceShell(id string, rw io.ReadWriter, opts structs.Insta
 func (p *Provider) InstanceTerminate(id string) error {
 	return fmt.Errorf("unimplemented")
 }
+
+func (p *Provider) InstanceUncordon(id string) error {
+	return fmt.Errorf("unimplemented")
+}
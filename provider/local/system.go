@@ -109,14 +109,12 @@ func (p *Provider) SystemInstall(name string, opts structs.SystemInstallOptions)
 		return "", fmt.Errorf("must be root to install a local rack")
 	}
 
-	if opts.Output != nil {
-		fmt.Fprintf(opts.Output, "pulling: convox/rack:%s\n", *opts.Version)
-	}
-
 	if opts.Version == nil {
 		return "", fmt.Errorf("must specify a version")
 	}
 
+	installEvent(opts, fmt.Sprintf("convox/rack:%s", *opts.Version), "pulling")
+
 	if err := launcherInstall("router", opts, exe, "router"); err != nil {
 		return "", err
 	}
@@ -148,6 +146,10 @@ func (p *Provider) SystemLogs(opts structs.LogsOptions) (io.ReadCloser, error) {
 		args = append(args, "--since", opts.Since.Format(time.RFC3339))
 	}
 
+	if !opts.Until.IsZero() {
+		args = append(args, "--until", opts.Until.Format(time.RFC3339))
+	}
+
 	args = append(args, hostname)
 
 	cmd := exec.Command("docker", args...)
@@ -177,6 +179,11 @@ func (p *Provider) SystemOptions() (map[string]string, error) {
 	return options, log.Success()
 }
 
+// SystemParameterDefinitions fails since local racks run an image and have no template
+func (p *Provider) SystemParameterDefinitions() (structs.ParameterDefinitions, error) {
+	return nil, fmt.Errorf("parameter definitions are not supported by this rack")
+}
+
 func (p *Provider) SystemProcesses(opts structs.SystemProcessesOptions) (structs.Processes, error) {
 	return nil, fmt.Errorf("unimplemented")
 }
@@ -208,13 +215,30 @@ func (p *Provider) SystemUninstall(name string, opts structs.SystemUninstallOpti
 		return fmt.Errorf("must be root to uninstall a local rack")
 	}
 
-	launcherRemove("rack")
-	launcherRemove(fmt.Sprintf("rack.%s", name))
+	launchers := []string{"rack", fmt.Sprintf("rack.%s", name)}
+
+	for _, l := range launchers {
+		uninstallEvent(opts, launcherPath(l), "deleting")
+	}
+
+	for _, l := range launchers {
+		launcherRemove(l)
+		uninstallEvent(opts, launcherPath(l), "deleted")
+	}
 
 	return nil
 }
 
 func (p *Provider) SystemUpdate(opts structs.SystemUpdateOptions) error {
+	if opts.Canary != nil && *opts.Canary {
+		return fmt.Errorf("canary updates are not supported by this rack")
+	}
+
+	// local racks run an image, there is no template to check a digest against
+	if opts.TemplateDigest != nil {
+		return fmt.Errorf("verified updates are not supported by this rack")
+	}
+
 	log := p.logger("SystemUpdate").Append("version=%q", opts.Version)
 
 	w := opts.Output
@@ -260,9 +284,7 @@ func launcherInstall(name string, opts structs.SystemInstallOptions, command str
 
 	path := launcherPath(name)
 
-	if opts.Output != nil {
-		fmt.Fprintf(opts.Output, "installing: %s\n", path)
-	}
+	installEvent(opts, path, "installing")
 
 	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
 		return err
@@ -272,14 +294,48 @@ func launcherInstall(name string, opts structs.SystemInstallOptions, command str
 		return err
 	}
 
+	installEvent(opts, path, "started")
+
 	return nil
 }
 
+// installEvent reports install progress as a structured event when requested, otherwise as text
+func installEvent(opts structs.SystemInstallOptions, resource, status string) {
+	if opts.Events != nil {
+		opts.Events(structs.SystemInstallEvent{
+			Message:   fmt.Sprintf("%s: %s", status, resource),
+			Resource:  resource,
+			Status:    status,
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	if opts.Output != nil && status != "started" {
+		fmt.Fprintf(opts.Output, "%s: %s\n", status, resource)
+	}
+}
+
+// uninstallEvent reports uninstall progress as a structured event when requested, otherwise as text
+func uninstallEvent(opts structs.SystemUninstallOptions, resource, status string) {
+	if opts.Events != nil {
+		opts.Events(structs.SystemUninstallEvent{
+			Message:   fmt.Sprintf("%s: %s", status, resource),
+			Resource:  resource,
+			Status:    status,
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	if opts.Output != nil && status == "deleting" {
+		fmt.Fprintf(opts.Output, "removing: %s\n", resource)
+	}
+}
+
 func launcherRemove(name string) error {
 	path := launcherPath(name)
 
-	fmt.Printf("removing: %s\n", path)
-
 	launcherStop(name)
 
 	os.Remove(path)
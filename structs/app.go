@@ -6,6 +6,7 @@ type App struct {
 	Release    string `json:"release"`
 	Sleep      bool   `json:"sleep"`
 	Status     string `json:"status"`
+	Version    string `json:"version,omitempty"`
 
 	Outputs    map[string]string `json:"-"`
 	Parameters map[string]string `json:"-"`
@@ -10,6 +10,7 @@ import (
 
 type Instance struct {
 	Agent     bool      `json:"agent"`
+	Cordoned  bool      `json:"cordoned"`
 	Cpu       float64   `json:"cpu"`
 	Id        string    `json:"id"`
 	Memory    float64   `json:"memory"`
@@ -18,6 +19,8 @@ type Instance struct {
 	PublicIp  string    `json:"public-ip"`
 	Status    string    `json:"status"`
 	Started   time.Time `json:"started"`
+	Type      string    `json:"type,omitempty"`
+	Zone      string    `json:"zone,omitempty"`
 }
 
 type Instances []Instance
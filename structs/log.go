@@ -7,4 +7,5 @@ type LogsOptions struct {
 	Follow bool
 	Prefix bool
 	Since  time.Time
+	Until  time.Time
 }
@@ -471,6 +471,20 @@ func (_m *MockProvider) Initialize(opts ProviderOptions) error {
 	return r0
 }
 
+// InstanceCordon provides a mock function with given fields: id
+func (_m *MockProvider) InstanceCordon(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // InstanceKeyroll provides a mock function with given fields:
 func (_m *MockProvider) InstanceKeyroll() error {
 	ret := _m.Called()
@@ -536,6 +550,20 @@ func (_m *MockProvider) InstanceTerminate(id string) error {
 	return r0
 }
 
+// InstanceUncordon provides a mock function with given fields: id
+func (_m *MockProvider) InstanceUncordon(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ObjectDelete provides a mock function with given fields: app, key
 func (_m *MockProvider) ObjectDelete(app string, key string) error {
 	ret := _m.Called(app, key)
@@ -1310,6 +1338,29 @@ func (_m *MockProvider) SystemLogs(opts LogsOptions) (io.ReadCloser, error) {
 	return r0, r1
 }
 
+// SystemParameterDefinitions provides a mock function with given fields:
+func (_m *MockProvider) SystemParameterDefinitions() (ParameterDefinitions, error) {
+	ret := _m.Called()
+
+	var r0 ParameterDefinitions
+	if rf, ok := ret.Get(0).(func() ParameterDefinitions); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ParameterDefinitions)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SystemProcesses provides a mock function with given fields: opts
 func (_m *MockProvider) SystemProcesses(opts SystemProcessesOptions) (Processes, error) {
 	ret := _m.Called(opts)
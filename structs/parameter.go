@@ -0,0 +1,18 @@
+package structs
+
+// ParameterDefinition describes a parameter as declared in the rack template
+type ParameterDefinition struct {
+	AllowedPattern        string   `json:"AllowedPattern"`
+	AllowedValues         []string `json:"AllowedValues"`
+	ConstraintDescription string   `json:"ConstraintDescription"`
+	Default               string   `json:"Default"`
+	Description           string   `json:"Description"`
+	MaxLength             string   `json:"MaxLength"`
+	MaxValue              string   `json:"MaxValue"`
+	MinLength             string   `json:"MinLength"`
+	MinValue              string   `json:"MinValue"`
+	NoEcho                bool     `json:"NoEcho"`
+	Type                  string   `json:"Type"`
+}
+
+type ParameterDefinitions map[string]ParameterDefinition
@@ -36,10 +36,12 @@ type Provider interface {
 	FilesDelete(app, pid string, files []string) error
 	FilesUpload(app, pid string, r io.Reader) error
 
+	InstanceCordon(id string) error
 	InstanceKeyroll() error
 	InstanceList() (Instances, error)
 	InstanceShell(id string, rw io.ReadWriter, opts InstanceShellOptions) error
 	InstanceTerminate(id string) error
+	InstanceUncordon(id string) error
 
 	ObjectDelete(app, key string) error
 	ObjectExists(app, key string) (bool, error)
@@ -85,6 +87,7 @@ type Provider interface {
 	SystemGet() (*System, error)
 	SystemInstall(name string, opts SystemInstallOptions) (string, error)
 	SystemLogs(opts LogsOptions) (io.ReadCloser, error)
+	SystemParameterDefinitions() (ParameterDefinitions, error)
 	SystemProcesses(opts SystemProcessesOptions) (Processes, error)
 	SystemReleases() (Releases, error)
 	SystemUninstall(name string, opts SystemUninstallOptions) error
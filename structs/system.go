@@ -1,6 +1,10 @@
 package structs
 
-import "io"
+import (
+	"fmt"
+	"io"
+	"time"
+)
 
 type System struct {
 	Count      int               `json:"count"`
@@ -9,6 +13,7 @@ type System struct {
 	Image      string            `json:"image"`
 	Outputs    map[string]string `json:"outputs,omitempty"`
 	Parameters map[string]string `json:"parameters,omitempty"`
+	Pin        string            `json:"pin,omitempty"`
 	Provider   string            `json:"provider"`
 	Region     string            `json:"region"`
 	Status     string            `json:"status"`
@@ -16,8 +21,16 @@ type System struct {
 	Version    string            `json:"version"`
 }
 
+type SystemInstallEvent struct {
+	Message   string    `json:"message"`
+	Resource  string    `json:"resource"`
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
 type SystemInstallOptions struct {
 	Color    *bool
+	Events   func(SystemInstallEvent)
 	Output   io.Writer
 	Password *string
 	Version  *string
@@ -27,16 +40,43 @@ type SystemProcessesOptions struct {
 	All *bool
 }
 
+// SystemUninstallEvent reports a resource being deleted, every resource is announced as
+// deleting before the first is deleted so the total is known up front
+type SystemUninstallEvent struct {
+	Message   string    `json:"message"`
+	Resource  string    `json:"resource"`
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
 type SystemUninstallOptions struct {
 	Color  *bool
+	Events func(SystemUninstallEvent)
 	Output io.Writer
 }
 
+// SystemRangeMin is the smallest instance count the autoscaling bounds can be set to
+const SystemRangeMin = 3
+
+// SystemRangeError rejects an autoscaling bound below SystemRangeMin, the api and the cli both
+// use it so they refuse the same values with the same message
+func SystemRangeError(name string) error {
+	return fmt.Errorf("%s must be at least %d", name, SystemRangeMin)
+}
+
 type SystemUpdateOptions struct {
+	Canary        *bool
 	InstanceCount *int
+	InstanceMax   *int
+	InstanceMin   *int
 	InstanceType  *string
 	Output        io.Writer
 	Parameters    map[string]string
 	Password      *string
-	Version       *string
+
+	// TemplateDigest is the sha256 of the template the caller verified, the update fails unless
+	// the template for Version matches it
+	TemplateDigest *string
+
+	Version *string
 }